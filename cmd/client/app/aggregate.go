@@ -0,0 +1,158 @@
+package app
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/metrics"
+)
+
+const (
+	// aggregateTick 집계 구간이 끝난 이벤트를 확인하는 최대 주기
+	aggregateTick = time.Second
+)
+
+// aggregate 같은 대상(regarding.uid)과 reason의 이벤트 집계
+type aggregate struct {
+	start  time.Time      // 구간 시작 (첫 이벤트 수신 시각)
+	count  int            // 첫 이벤트 이후 발생한 횟수
+	seen   map[string]int // 이벤트 객체(metadata.uid)별로 마지막에 확인한 누적 발생 횟수
+	first  time.Time
+	last   time.Time
+	latest *kube.Event // 첫 이벤트 이후 마지막으로 수신한 이벤트 (없으면 nil)
+}
+
+// aggregator 집계 구간(window) 동안 반복된 이벤트를 하나의 메시지로 묶음
+// 구간의 첫 이벤트는 바로 전송하고, 이후 반복된 이벤트는 구간이 끝날 때 마지막 이벤트에 횟수와 처음/마지막 발생 시각을 담아 한 번 전송
+// 횟수는 이미 전송한 첫 이벤트를 제외하고 series.count, deprecatedCount 증가분으로 계산 (update 횟수가 아님)
+type aggregator struct {
+	window   time.Duration
+	sendFunc func(event *kube.Event)
+
+	mu      sync.Mutex
+	pending map[string]*aggregate
+
+	running   atomic.Bool
+	closeChan chan struct{}
+	doneChan  chan struct{}
+	closeOnce sync.Once
+}
+
+func newAggregator(window time.Duration, sendFunc func(event *kube.Event)) *aggregator {
+	return &aggregator{
+		window:    window,
+		sendFunc:  sendFunc,
+		pending:   make(map[string]*aggregate),
+		closeChan: make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+}
+
+// add 이벤트를 집계하고 바로 전송해야 하는지 반환
+// 구간 안에서 반복된 이벤트는 false를 반환하고 구간이 끝날 때 전송
+func (a *aggregator) add(event *kube.Event) bool {
+	if event.Regarding.UID == "" {
+		return true
+	}
+	key := event.Regarding.UID + "/" + event.Reason
+	occurred := event.OccurredAt()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agg, ok := a.pending[key]
+	if !ok {
+		a.pending[key] = &aggregate{
+			start: time.Now(),
+			seen:  map[string]int{event.Metadata.UID: event.Occurrences()},
+			first: occurred,
+			last:  occurred,
+		}
+		return true
+	}
+
+	// 처음 보는 이벤트 객체는 누적 횟수 전체, 이미 본 객체는 증가분만 더함
+	occurrences := event.Occurrences()
+	if prev, ok := agg.seen[event.Metadata.UID]; !ok {
+		agg.count += occurrences
+	} else if occurrences > prev {
+		agg.count += occurrences - prev
+	}
+	if occurrences > agg.seen[event.Metadata.UID] {
+		agg.seen[event.Metadata.UID] = occurrences
+	}
+	if occurred.Before(agg.first) {
+		agg.first = occurred
+	}
+	if occurred.After(agg.last) {
+		agg.last = occurred
+	}
+	agg.latest = event
+	metrics.InformerDeduplicated.WithLabelValues("aggregated").Inc()
+	return false
+}
+
+// flush 구간이 끝난 집계를 전송 (all이면 모든 집계)
+func (a *aggregator) flush(all bool) {
+	now := time.Now()
+	events := make([]*kube.Event, 0)
+
+	a.mu.Lock()
+	for key, agg := range a.pending {
+		if !all && now.Sub(agg.start) < a.window {
+			continue
+		}
+		delete(a.pending, key)
+		if agg.latest == nil {
+			continue
+		}
+		agg.latest.Aggregation = &kube.Aggregation{
+			Count:          agg.count,
+			FirstTimestamp: agg.first,
+			LastTimestamp:  agg.last,
+		}
+		events = append(events, agg.latest)
+	}
+	a.mu.Unlock()
+
+	for _, event := range events {
+		a.sendFunc(event)
+	}
+}
+
+// Run 구간이 끝난 집계를 주기적으로 전송
+func (a *aggregator) Run() {
+	a.running.Store(true)
+	defer close(a.doneChan)
+
+	tick := aggregateTick
+	if a.window < tick {
+		tick = a.window
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.closeChan:
+			return
+		case <-ticker.C:
+			a.flush(false)
+		}
+	}
+}
+
+// Close 주기 전송을 멈추고 남은 집계를 모두 전송
+// informer 종료 후, kafka producer 종료 전에 호출
+func (a *aggregator) Close() {
+	a.closeOnce.Do(func() {
+		close(a.closeChan)
+	})
+	if a.running.Load() {
+		<-a.doneChan
+	}
+
+	a.flush(true)
+}
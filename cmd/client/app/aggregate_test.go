@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"example.com/stradvision-project/pkg/kube"
+)
+
+func testEvent(uid, reason, name string, count int, last time.Time) *kube.Event {
+	event := &kube.Event{Reason: reason, DeprecatedCount: count, DeprecatedLastTimestamp: last}
+	event.Metadata.UID = name
+	event.Regarding.UID = uid
+	return event
+}
+
+func TestAggregator(t *testing.T) {
+	sent := make([]*kube.Event, 0)
+	agg := newAggregator(time.Minute, func(event *kube.Event) {
+		sent = append(sent, event)
+	})
+
+	base := time.Date(2025, 3, 6, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		event *kube.Event
+		want  bool
+	}{
+		{testEvent("pod-1", "BackOff", "e1", 1, base), true},                      // 구간의 첫 이벤트는 바로 전송
+		{testEvent("pod-1", "BackOff", "e1", 3, base.Add(10*time.Second)), false}, // 반복 이벤트는 집계 (2회 증가)
+		{testEvent("pod-1", "BackOff", "e1", 3, base.Add(10*time.Second)), false}, // 횟수가 늘지 않은 update는 세지 않음
+		{testEvent("pod-1", "BackOff", "e2", 0, base.Add(20*time.Second)), false}, // 새 이벤트 객체는 1회
+		{testEvent("pod-1", "Pulled", "e3", 1, base), true},                       // reason이 다르면 별도 집계
+		{testEvent("pod-2", "BackOff", "e4", 1, base), true},
+		{testEvent("", "BackOff", "e5", 1, base), true}, // 대상이 없으면 집계하지 않음
+	}
+	for i, tt := range tests {
+		if got := agg.add(tt.event); got != tt.want {
+			t.Errorf("add() #%d = %v, want %v", i, got, tt.want)
+		}
+	}
+
+	// 구간이 끝나지 않았으면 전송하지 않음
+	agg.flush(false)
+	if len(sent) != 0 {
+		t.Fatalf("flush(false) sent %d, want %d", len(sent), 0)
+	}
+
+	// 반복된 이벤트만 마지막 이벤트에 집계 정보를 담아 한 번 전송 (이미 전송한 첫 이벤트는 횟수에서 제외)
+	agg.Close()
+	if len(sent) != 1 {
+		t.Fatalf("Close() sent %d, want %d", len(sent), 1)
+	}
+	got := sent[0].Aggregation
+	if got == nil || got.Count != 3 || !got.FirstTimestamp.Equal(base) || !got.LastTimestamp.Equal(base.Add(20*time.Second)) {
+		t.Errorf("Aggregation = %+v, want count 3 from %s to %s", got, base, base.Add(20*time.Second))
+	}
+}
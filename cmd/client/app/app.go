@@ -1,26 +1,108 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"example.com/stradvision-project/cmd/client/config"
 	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/leader"
 	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultShutdownTimeout 종료 시 남은 이벤트 처리를 기다리는 최대 시간
+	// kubernetes terminationGracePeriodSeconds(30초)보다 짧아야 함
+	DefaultShutdownTimeout = 25 * time.Second
 )
 
 type Application struct {
-	k8sClient *kube.Client
-	kp        *producer.KafkaProducer
+	// 설정 reload 시 교체
+	clusters   []*kubeCluster
+	clustersMu sync.RWMutex
+	informing  bool // informer 실행 여부 (leader election 사용 시 leader가 된 후)
+	kp         *sender
+
+	aggregator *aggregator  // 설정하지 않으면 nil
+	resume     *kube.Resume // 설정하지 않으면 nil
+	// handler의 전송 기록 (resume을 설정하지 않으면 메모리에만 유지)
+	// cluster를 다시 만들 때 최초 LIST로 이미 전송한 리소스를 다시 전송하지 않도록 항상 유지
+	sent *kube.Resume
+	// 전송한 이벤트 (삭제 처리 시 전송하지 않은 이벤트 확인)
+	delivered *deliveredEvents
+
+	// leader election (설정하지 않으면 nil)
+	elector      *leader.Elector
+	leaderCancel context.CancelFunc
+	leaderDone   chan struct{}
+	lostChan     chan struct{} // leader를 잃으면 닫힘
+	lostOnce     sync.Once
+	stopping     atomic.Bool
+
+	// metrics
+	server *metrics.Server
 
-	handler *Handler
+	config   *config.Config // 마지막으로 적용한 설정
+	reloadMu sync.Mutex     // Reload, shutdown 동시 실행 방지
 }
 
 func NewApplication(config *config.Config) (*Application, error) {
+	app := &Application{
+		server:   metrics.NewServer(config.Server.Address),
+		lostChan: make(chan struct{}),
+		config:   config,
+	}
+
 	// Kafka producer
+	kp, err := newProducer(config)
+	if err != nil {
+		return nil, err
+	}
+	app.kp = newSender(kp)
+
+	// kuberentes client (cluster별 informer)
+	clusters, err := newClusters(config, app.kp)
+	if err != nil {
+		return nil, err
+	}
+	app.clusters = clusters
+
+	// leader election, resume ConfigMap은 첫 번째 cluster 사용
+	app.resume = newResume(config, clusters[0].client)
+	app.delivered = newDeliveredEvents(config.Kube.EventTTL)
+	app.sent = app.resume
+	if app.sent == nil {
+		app.sent = kube.NewResume(nil, kube.WithResumeMaxSize(config.Resume.MaxSize))
+	}
+	if config.Aggregation.Window > 0 {
+		// 집계한 이벤트는 문서의 cluster로 전송되므로 어느 cluster의 handler를 사용해도 같음
+		app.aggregator = newAggregator(config.Aggregation.Window, func(event *kube.Event) {
+			app.currentClusters()[0].handler.send("Aggregate", event)
+		})
+	}
+	app.setHandlers(clusters, config)
+
+	if config.LeaderElection.Enabled {
+		if app.elector, err = app.newElector(config); err != nil {
+			return nil, fmt.Errorf("failed to create leader elector: %w", err)
+		}
+	}
+	app.registerHealth()
+
+	return app, nil
+}
+
+// newProducer kafka producer 생성
+func newProducer(config *config.Config) (*producer.KafkaProducer, error) {
 	kp, err := producer.NewKafkaProducer(
 		config.Kafka.Broker, config.Kafka.Topic,
 		producer.WithTimeout(config.Kafka.Timeout),
@@ -29,45 +111,127 @@ func NewApplication(config *config.Config) (*Application, error) {
 		producer.WithFlushMaxMessages(config.Kafka.FlushMsg),
 		producer.WithFlushFrequency(config.Kafka.FlushTime),
 		producer.WithFlushBytes(config.Kafka.FlushByte),
+		producer.WithTLS(config.Kafka.TLS),
+		producer.WithSASL(config.Kafka.SASL),
+		// 같은 key(대상 리소스 uid)의 메시지가 항상 같은 파티션으로 가야 리소스별 순서가 유지됨 (key가 없으면 랜덤)
+		producer.WithPartitioner(producer.PartitionerHash),
 		producer.WithErrorFunc(kafkaErrorHandler),
 		producer.WithSuccessFunc(kafkaSuccessHandler),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
 	}
+	return kp, nil
+}
 
-	// kuberentes client
-	handler := &Handler{kp: kp}
-	kc, err := kube.NewClient(
-		handler,
-		kube.WithKubeConfig(config.Kube.Config),
-		kube.WithResyncTime(config.Kube.Resync),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+// setHandlers cluster별 handler에 전송 기록, 집계, 삭제 처리 설정
+func (app *Application) setHandlers(clusters []*kubeCluster, config *config.Config) {
+	app.delivered.setTTL(config.Kube.EventTTL)
+	for _, c := range clusters {
+		c.handler.resume = app.sent
+		c.handler.delivered = app.delivered
+		c.handler.aggregator = app.aggregator
+		c.handler.handleDelete = config.Kube.HandleDelete
 	}
+}
 
-	return &Application{
-		k8sClient: kc,
-		kp:        kp,
-		handler:   handler,
-	}, nil
+// currentClusters 현재 수집 중인 cluster 목록
+func (app *Application) currentClusters() []*kubeCluster {
+	app.clustersMu.RLock()
+	defer app.clustersMu.RUnlock()
+	return app.clusters
 }
 
-func (app *Application) Run() {
+// Run application
+// leader election을 사용하면 leader가 된 후에 informer를 시작하고, leader를 잃으면 에러 반환
+func (app *Application) Run() error {
 	logger.Info("start application ...")
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	go app.kp.Run()
-	go app.k8sClient.Run()
+	go app.kp.Producer().Run()
+	go app.runServer()
+	if app.aggregator != nil {
+		go app.aggregator.Run()
+	}
+	if app.elector != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		app.leaderCancel = cancel
+		app.leaderDone = make(chan struct{})
+		go func() {
+			defer close(app.leaderDone)
+			app.elector.Run(ctx)
+		}()
+	} else {
+		go app.runInformer()
+	}
+
+	var runErr error
+	select {
+	case <-sigChan:
+	case <-app.lostChan:
+		runErr = fmt.Errorf("lost leadership")
+	}
 
-	<-sigChan
-	app.k8sClient.Close()
-	logger.Info("closed kubernetes client")
-	app.kp.Close()
-	logger.Info("closed kafka producer")
+	app.shutdown()
 
 	logger.Info("stop application ...")
+	return runErr
+}
+
+// shutdown informer 중지, 집계 중인 이벤트 전송, kafka 전송 결과 대기, 전송 기록 저장, lease 반납 순서로 종료
+// lease는 전송이 끝난 뒤 반납하여 standby와 동시에 수집하지 않도록 함
+// DefaultShutdownTimeout 안에 끝나지 않으면 기다리지 않고 종료
+func (app *Application) shutdown() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// 진행 중인 reload가 끝난 후 종료 (종료 중 leader를 잃은 것은 무시)
+		app.reloadMu.Lock()
+		app.stopping.Store(true)
+		app.reloadMu.Unlock()
+
+		for _, c := range app.currentClusters() {
+			c.client.Close()
+			logger.Info("closed kubernetes client", zap.String("cluster", c.name))
+		}
+		if app.aggregator != nil {
+			app.aggregator.Close()
+			logger.Info("flushed event aggregation")
+		}
+		app.kp.Producer().Close()
+		logger.Info("closed kafka producer")
+
+		if app.resume != nil {
+			if err := app.resume.Close(); err != nil {
+				logger.Error("failed to save resume", zap.Error(err))
+			} else {
+				logger.Info("saved resume")
+			}
+		}
+
+		if app.leaderCancel != nil {
+			app.leaderCancel()
+			<-app.leaderDone
+			logger.Info("released leader lease")
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(DefaultShutdownTimeout):
+		logger.Error("shutdown timed out", zap.Duration("timeout", DefaultShutdownTimeout))
+	}
+
+	app.server.Close()
+	logger.Info("closed metrics server")
+}
+
+// runServer metrics http 서버 실행
+func (app *Application) runServer() {
+	if err := app.server.Run(); err != nil {
+		logger.Error("failed to run metrics server", zap.Error(err))
+	}
 }
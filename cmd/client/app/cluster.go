@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+
+	"example.com/stradvision-project/cmd/client/config"
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// kubeCluster 수집할 cluster의 kubernetes client와 event handler
+type kubeCluster struct {
+	name    string
+	client  *kube.Client
+	handler *Handler
+}
+
+// newClusters cluster별 kubernetes client 생성
+// kube.clusters가 없으면 kube.config, kube.context, kube.cluster로 하나의 cluster 생성
+// kp: 설정 reload로 교체되는 kafka producer
+func newClusters(cfg *config.Config, kp *sender) ([]*kubeCluster, error) {
+	resources, err := kube.ParseResources(cfg.Kube.Resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse kubernetes resources: %w", err)
+	}
+
+	clusters := cfg.Kube.Clusters
+	if len(clusters) == 0 {
+		clusters = []config.Cluster{{Name: cfg.Kube.Cluster, Config: cfg.Kube.Config, Context: cfg.Kube.Context}}
+	}
+
+	result := make([]*kubeCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		handler := &Handler{kp: kp, cluster: cluster.Name, key: cfg.Kafka.Key}
+		options := []kube.Option{
+			kube.WithKubeConfig(cluster.Config),
+			kube.WithContext(cluster.Context),
+			kube.WithResyncTime(cfg.Kube.Resync),
+			kube.WithResources(resources...),
+			kube.WithNamespaces(cfg.Kube.Namespaces...),
+			kube.WithExcludeNamespaces(cfg.Kube.ExcludeNamespaces...),
+			kube.WithFieldSelector(cfg.Kube.FieldSelector),
+			kube.WithLabelSelector(cfg.Kube.LabelSelector),
+			kube.WithErrorFunc(func(err error) {
+				logger.Warn("kubernetes client error", zap.String("cluster", cluster.Name), zap.Error(err))
+			}),
+		}
+		if cfg.Kube.Enrich.Enabled {
+			options = append(options, kube.WithEnrichment(cfg.Kube.Enrich.Labels, cfg.Kube.Enrich.Annotations))
+		}
+
+		kc, err := kube.NewClient(handler, options...)
+		if err != nil {
+			for _, c := range result {
+				c.client.Close()
+			}
+			return nil, fmt.Errorf("failed to create kubernetes client for cluster %q: %w", cluster.Name, err)
+		}
+		handler.enricher = kc.Enricher()
+
+		result = append(result, &kubeCluster{name: cluster.Name, client: kc, handler: handler})
+	}
+
+	return result, nil
+}
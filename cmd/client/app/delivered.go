@@ -0,0 +1,77 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// deliveredMargin TTL 만료 삭제가 늦어지는 경우를 위해 event TTL보다 더 유지하는 시간
+	deliveredMargin = 10 * time.Minute
+	// deliveredPruneInterval 만료된 기록을 정리하는 최소 간격
+	deliveredPruneInterval = time.Minute
+)
+
+// deliveredEvents 브로커가 전송을 확인한 이벤트 UID
+// 이벤트는 event TTL이 지나면 삭제되므로, 삭제 시점에 전송 여부를 확인할 수 있도록
+// 개수 제한 없이 전송 후 event TTL 동안 유지
+type deliveredEvents struct {
+	mu        sync.Mutex
+	retention time.Duration
+	sent      map[string]time.Time // UID → 전송 확인 시각
+	pruned    time.Time
+}
+
+func newDeliveredEvents(ttl time.Duration) *deliveredEvents {
+	return &deliveredEvents{retention: ttl + deliveredMargin, sent: make(map[string]time.Time)}
+}
+
+// setTTL event TTL 변경 (설정 reload)
+func (d *deliveredEvents) setTTL(ttl time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.retention = ttl + deliveredMargin
+}
+
+// mark 전송 확인 기록
+func (d *deliveredEvents) mark(uid string) {
+	if uid == "" {
+		return
+	}
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.sent[uid] = now
+	if now.Sub(d.pruned) >= deliveredPruneInterval {
+		d.prune(now)
+	}
+}
+
+// contains 전송 확인 기록이 있는지 여부
+func (d *deliveredEvents) contains(uid string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	sent, ok := d.sent[uid]
+	return ok && time.Since(sent) < d.retention
+}
+
+// prune retention이 지난 기록 정리
+func (d *deliveredEvents) prune(now time.Time) {
+	for uid, sent := range d.sent {
+		if now.Sub(sent) >= d.retention {
+			delete(d.sent, uid)
+		}
+	}
+	d.pruned = now
+}
+
+// len 기록된 UID 개수
+func (d *deliveredEvents) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.sent)
+}
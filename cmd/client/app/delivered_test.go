@@ -0,0 +1,26 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeliveredEvents(t *testing.T) {
+	d := newDeliveredEvents(time.Hour)
+	d.mark("a")
+	d.mark("b")
+	if !d.contains("a") || d.contains("c") {
+		t.Fatalf("contains(a), contains(c) = %v, %v, want true, false", d.contains("a"), d.contains("c"))
+	}
+
+	// event TTL과 여유 시간이 지난 기록은 정리
+	now := time.Now()
+	d.sent["a"] = now.Add(-time.Hour - deliveredMargin)
+	if d.contains("a") {
+		t.Errorf("contains(a) = true, want expired")
+	}
+	d.prune(now)
+	if d.len() != 1 {
+		t.Errorf("len() = %d, want 1", d.len())
+	}
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+
+	"example.com/stradvision-project/pkg/health"
+)
+
+// registerHealth liveness, readiness 확인 함수를 등록하고 metrics 서버에 endpoint 추가
+func (app *Application) registerHealth() {
+	h := health.NewHealth()
+
+	// kubernetes 이벤트를 kafka로 전송할 수 있어야 ready
+	// standby는 informer를 실행하지 않으므로 동기화 여부를 확인하지 않음
+	h.AddReadiness("kubernetes", func() error {
+		if app.elector != nil && !app.elector.IsLeader() {
+			return nil
+		}
+		// 여러 cluster에서 수집하면 하나 이상 동기화되었을 때 ready (cluster별 상태는 status로 확인)
+		for _, c := range app.currentClusters() {
+			if c.client.HasSynced() {
+				return nil
+			}
+		}
+		return fmt.Errorf("informer cache is not synced")
+	})
+	h.AddReadiness("kafka", func() error { return app.kp.Producer().Ping() })
+
+	// 설정 reload로 cluster가 다시 만들어질 수 있으므로 이름으로 조회 (추가된 cluster는 재시작 후 표시)
+	if len(app.clusters) > 1 {
+		for _, c := range app.clusters {
+			name := c.name
+			h.AddStatus("cluster "+name, func() string {
+				for _, c := range app.currentClusters() {
+					if c.name != name {
+						continue
+					}
+					if c.client.HasSynced() {
+						return "synced"
+					}
+					return "not synced"
+				}
+				return "removed"
+			})
+		}
+	}
+
+	if app.elector != nil {
+		h.AddStatus("leader", func() string {
+			if app.elector.IsLeader() {
+				return "leader"
+			}
+			return "standby (leader: " + app.elector.Leader() + ")"
+		})
+	}
+
+	app.server.Handle(health.LivenessPath, h.LivenessHandler())
+	app.server.Handle(health.ReadinessPath, h.ReadinessHandler())
+}
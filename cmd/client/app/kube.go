@@ -2,61 +2,233 @@ package app
 
 import (
 	"encoding/json"
+	"time"
 
 	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/kube"
 	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/metrics"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
 )
 
+const (
+	// consumer가 payload를 해석하지 않고 분류할 수 있도록 추가하는 kafka header
+	HeaderCluster       string = "cluster"        // 수집한 cluster 이름 (설정한 경우)
+	HeaderDocument      string = "document"       // event, object
+	HeaderKind          string = "kind"           // 이벤트는 대상 리소스 kind, 그 외 리소스는 kind
+	HeaderEventType     string = "event-type"     // 이벤트 type (Normal, Warning)
+	HeaderOperation     string = "operation"      // add, update, delete (설정된 경우)
+	HeaderSchemaVersion string = "schema-version" // 문서 형식 버전
+	HeaderProducedAt    string = "produced-at"    // 전송 시각 (RFC3339)
+)
+
+// Handler cluster별 informer event handler
 type Handler struct {
-	kp *producer.KafkaProducer
+	kp      *sender // 설정 reload 시 교체
+	cluster string  // 문서의 cluster 필드와 kafka header에 사용
+	key     string  // kafka 메시지 key 생성 방식 (kube.MessageKey*)
+
+	// 이벤트 대상 리소스 정보 추가 (설정하지 않으면 nil)
+	enricher *kube.Enricher
+	// 전송한 리소스 기록 (resume을 설정하지 않으면 메모리에만 유지, 재시작 전 기록 없음)
+	resume *kube.Resume
+	// 반복 이벤트 집계 (설정하지 않으면 nil)
+	aggregator *aggregator
+	// 삭제 처리 여부
+	handleDelete bool
+	// 전송한 이벤트 (handleDelete를 설정한 경우에만 기록)
+	delivered *deliveredEvents
 }
 
 // OnAdd event handler
-func (h *Handler) OnAdd(obj interface{}, _ bool) {
-	object := obj.(*v1.Event)
-	event := kube.ConvertEvent(object)
-
-	jsonData, err := json.Marshal(event)
-	if err != nil {
-		logger.Error("[OnAdd] failed to marshal event object", zap.Error(err))
+// 최초 LIST로 수신한 리소스 중 재시작 전에 이미 전송한 것은 제외
+func (h *Handler) OnAdd(obj interface{}, isInInitialList bool) {
+	metrics.InformerEvents.WithLabelValues("add").Inc()
+	doc, ok := h.convert("OnAdd", obj, kube.OperationAdd)
+	if !ok {
 		return
 	}
-
-	h.kp.SendMessage("", jsonData)
-	logger.Debug("[OnAdd] event object",
-		zap.String("Kind", event.Regarding.Kind),
-		zap.String("Namespace", event.Regarding.Namespace),
-		zap.String("Name", event.Regarding.Name),
-		zap.String("UID", event.Regarding.UID),
-		zap.String("Reason", event.Reason),
-	)
+	if isInInitialList && h.resume != nil && h.resume.Processed(doc) {
+		metrics.InformerSkipped.Inc()
+		return
+	}
+	h.aggregate("OnAdd", doc)
 }
 
 // OnUpdate event handler
+// resync 등으로 resourceVersion이 바뀌지 않은 update는 전송하지 않음
+// Event 이외의 리소스는 heartbeat 등 주기적으로 바뀌는 필드만 바뀐 update도 전송하지 않음
 func (h *Handler) OnUpdate(oldObj, newObj interface{}) {
-	object := newObj.(*v1.Event)
-	event := kube.ConvertEvent(object)
+	metrics.InformerEvents.WithLabelValues("update").Inc()
+	if unchanged(oldObj, newObj) {
+		metrics.InformerDeduplicated.WithLabelValues("unchanged").Inc()
+		return
+	}
+	if _, ok := newObj.(*v1.Event); !ok && !kube.ObjectChanged(oldObj, newObj) {
+		metrics.InformerDeduplicated.WithLabelValues("insignificant").Inc()
+		return
+	}
+	if doc, ok := h.convert("OnUpdate", newObj, kube.OperationUpdate); ok {
+		h.aggregate("OnUpdate", doc)
+	}
+}
+
+// OnDelete event handler
+// handleDelete를 설정한 경우에만 처리
+// 이벤트는 브로커가 전송을 확인한 기록(event TTL 동안 유지, 재시작 전 기록은 resume)이 없는 경우에만
+// 마지막 상태를 전송하고 (TTL 만료 시 연결이 끊겨 수신하지 못한 이벤트)
+// 그 외 리소스는 삭제 기록(operation: delete)을 전송
+func (h *Handler) OnDelete(obj interface{}) {
+	if !h.handleDelete {
+		return
+	}
+	metrics.InformerEvents.WithLabelValues("delete").Inc()
+
+	// watch가 끊긴 동안 삭제된 경우 마지막으로 알고 있는 상태가 전달됨
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	doc, ok := h.convert("OnDelete", obj, kube.OperationDelete)
+	if !ok {
+		return
+	}
+	if _, ok := doc.(*kube.Event); ok && (h.delivered.contains(doc.Meta().UID) || h.resume.Contains(doc)) {
+		return
+	}
+	h.send("OnDelete", doc)
+}
 
-	jsonData, err := json.Marshal(event)
+// unchanged 이전 리소스와 resourceVersion이 같은지 여부
+func unchanged(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
 	if err != nil {
-		logger.Error("[OnUpdate] failed to marshal event object", zap.Error(err))
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+// aggregate 이벤트는 집계 구간 안에서 반복되면 전송하지 않고, 그 외 문서는 바로 전송
+func (h *Handler) aggregate(handler string, doc kube.Document) {
+	if event, ok := doc.(*kube.Event); ok && h.aggregator != nil {
+		if !h.aggregator.add(event) {
+			return
+		}
+	}
+	h.send(handler, doc)
+}
+
+// convert 리소스를 문서로 변환
+// Event는 kube.Event(대상 리소스 정보 추가, 삭제된 경우에만 operation 설정), 그 외 리소스는 kube.Object로 변환
+func (h *Handler) convert(handler string, obj interface{}, operation string) (kube.Document, bool) {
+	if object, ok := obj.(*v1.Event); ok {
+		event := kube.ConvertEvent(object)
+		event.Cluster = h.cluster
+		if h.enricher != nil {
+			h.enricher.Enrich(event)
+		}
+		if operation == kube.OperationDelete {
+			event.Operation = operation
+		}
+		return event, true
+	}
+
+	object, err := kube.ConvertObject(obj)
+	if err != nil {
+		logger.Error("["+handler+"] failed to convert object", zap.Error(err))
+		return nil, false
+	}
+	object.Operation = operation
+	object.Cluster = h.cluster
+	return object, true
+}
+
+// send 문서를 kafka로 전송
+// 브로커로부터 전송 성공 응답을 받은 문서만 전송 기록에 추가 (실패하면 재시작 후 다시 전송)
+func (h *Handler) send(handler string, doc kube.Document) {
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		logger.Error("["+handler+"] failed to marshal object", zap.Error(err))
 		return
 	}
 
-	h.kp.SendMessage("", jsonData)
-	logger.Debug("[OnUpdate] event object",
-		zap.String("Kind", event.Regarding.Kind),
-		zap.String("Namespace", event.Regarding.Namespace),
-		zap.String("Name", event.Regarding.Name),
-		zap.String("UID", event.Regarding.UID),
-		zap.String("Reason", event.Reason),
-	)
+	now := time.Now()
+	message := producer.Message{
+		Key:       kube.MessageKey(doc, h.key),
+		Value:     jsonData,
+		Headers:   headers(doc, now),
+		Timestamp: now,
+	}
+	resume, delivered := h.resume, h.delivered
+	_, isEvent := doc.(*kube.Event)
+	trackDelivered := isEvent && h.handleDelete && delivered != nil
+	if resume != nil || trackDelivered {
+		message.Ack = func() {
+			if resume != nil {
+				resume.Mark(doc)
+			}
+			if trackDelivered {
+				delivered.mark(doc.Meta().UID)
+			}
+		}
+	}
+	h.kp.Send(message)
+	logDocument(handler, doc)
 }
 
-// OnDelete event handler
-func (h *Handler) OnDelete(obj interface{}) {
-	// delete의 경우는 데이터를 수집할 필요가 없으므로 구현하지 않음
+// headers 문서의 kafka header
+// 집계한 이벤트는 다른 cluster의 handler로 전송될 수 있으므로 문서의 cluster를 사용
+func headers(doc kube.Document, producedAt time.Time) map[string]string {
+	result := map[string]string{
+		HeaderSchemaVersion: kube.SchemaVersion,
+		HeaderProducedAt:    producedAt.UTC().Format(time.RFC3339Nano),
+	}
+
+	var cluster, operation string
+	switch d := doc.(type) {
+	case *kube.Event:
+		result[HeaderDocument] = "event"
+		result[HeaderKind] = d.Regarding.Kind
+		result[HeaderEventType] = d.Type
+		cluster, operation = d.Cluster, d.Operation
+	case *kube.Object:
+		result[HeaderDocument] = "object"
+		result[HeaderKind] = d.Kind
+		cluster, operation = d.Cluster, d.Operation
+	}
+	if cluster != "" {
+		result[HeaderCluster] = cluster
+	}
+	if operation != "" {
+		result[HeaderOperation] = operation
+	}
+	return result
+}
+
+// logDocument 전송한 문서 debug 로그
+func logDocument(handler string, doc kube.Document) {
+	switch d := doc.(type) {
+	case *kube.Event:
+		logger.Debug("["+handler+"] event object",
+			zap.String("Kind", d.Regarding.Kind),
+			zap.String("Namespace", d.Regarding.Namespace),
+			zap.String("Name", d.Regarding.Name),
+			zap.String("UID", d.Regarding.UID),
+			zap.String("Reason", d.Reason),
+		)
+	case *kube.Object:
+		logger.Debug("["+handler+"] resource object",
+			zap.String("Kind", d.Kind),
+			zap.String("Namespace", d.Metadata.Namespace),
+			zap.String("Name", d.Metadata.Name),
+			zap.String("UID", d.Metadata.UID),
+		)
+	}
 }
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"os"
+
+	"example.com/stradvision-project/cmd/client/config"
+	"example.com/stradvision-project/pkg/leader"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultLeaseName string = "stradvision-client"
+)
+
+// newElector leader election 설정이 있으면 Elector 생성
+// leader가 되면 informer를 시작하고, leader를 잃으면 lostChan을 닫아 application을 종료
+func (app *Application) newElector(config *config.Config) (*leader.Elector, error) {
+	name := config.LeaderElection.Name
+	if name == "" {
+		name = DefaultLeaseName
+	}
+	identity := config.LeaderElection.Identity
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+
+	return leader.NewElector(
+		app.clusters[0].client.Clientset(),
+		config.LeaderElection.Namespace, name, identity,
+		app.onStartedLeading,
+		app.onStoppedLeading,
+		leader.WithLeaseDuration(config.LeaderElection.LeaseDuration),
+		leader.WithRenewDeadline(config.LeaderElection.RenewDeadline),
+		leader.WithRetryPeriod(config.LeaderElection.RetryPeriod),
+		leader.WithNewLeaderFunc(func(identity string) {
+			logger.Info("leader changed", zap.String("leader", identity))
+		}),
+	)
+}
+
+// onStartedLeading leader가 되면 informer 시작
+func (app *Application) onStartedLeading(ctx context.Context) {
+	logger.Info("became leader", zap.String("identity", app.elector.Identity()))
+	go app.runInformer()
+}
+
+// onStoppedLeading leader를 잃으면 application 종료 (종료 중 lease 반납은 제외)
+// 종료된 informer는 다시 시작할 수 없으므로 프로세스를 재시작하여 standby로 다시 참여
+func (app *Application) onStoppedLeading() {
+	if app.stopping.Load() {
+		return
+	}
+
+	logger.Error("lost leadership", zap.String("identity", app.elector.Identity()))
+	app.lostOnce.Do(func() {
+		close(app.lostChan)
+	})
+}
@@ -1,16 +1,64 @@
 package app
 
 import (
-	"time"
+	"sync"
 
+	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/logger"
 	"go.uber.org/zap"
 )
 
-func kafkaErrorHandler(ts time.Time, topic string, partition int32, err error) {
-	logger.Error("failed kafka send message", zap.Time("ts", ts), zap.String("topic", topic), zap.Int32("partition", partition), zap.Error(err))
+// sender 설정 reload 시 교체되는 kafka producer
+// 교체하는 동안 전송을 멈춰서 종료된 이전 producer로 전송하지 않도록 함
+type sender struct {
+	mu sync.RWMutex
+	kp *producer.KafkaProducer
 }
 
-func kafkaSuccessHandler(ts time.Time, topic string, partition int32) {
-	logger.Debug("success kafka send message", zap.Time("ts", ts), zap.String("topic", topic), zap.Int32("partition", partition))
+func newSender(kp *producer.KafkaProducer) *sender {
+	return &sender{kp: kp}
+}
+
+// Send 현재 producer로 메시지 전송
+func (s *sender) Send(message producer.Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	s.kp.Send(message)
+}
+
+// Producer 현재 producer
+func (s *sender) Producer() *producer.KafkaProducer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.kp
+}
+
+// swap 진행 중인 전송이 끝나면 새 producer로 교체하고 이전 producer 반환
+func (s *sender) swap(kp *producer.KafkaProducer) *producer.KafkaProducer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.kp
+	s.kp = kp
+	return old
+}
+
+func kafkaErrorHandler(result *producer.Result) {
+	logger.Error("failed kafka send message",
+		zap.Time("ts", result.Timestamp),
+		zap.String("topic", result.Topic),
+		zap.Int32("partition", result.Partition),
+		zap.String("key", result.Message.Key),
+		zap.Any("headers", result.Message.Headers),
+		zap.Error(result.Err),
+	)
+}
+
+func kafkaSuccessHandler(result *producer.Result) {
+	logger.Debug("success kafka send message",
+		zap.Time("ts", result.Timestamp),
+		zap.String("topic", result.Topic),
+		zap.Int32("partition", result.Partition),
+		zap.Int64("offset", result.Offset),
+		zap.String("key", result.Message.Key),
+	)
 }
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"reflect"
+
+	"example.com/stradvision-project/cmd/client/config"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Reload 변경된 설정을 실행 중에 적용
+// kafka 연결 설정(주소, 인증, TLS, 전송 옵션 등)이 바뀌면 producer만 새로 만들어 교체하고
+// 수집 대상(cluster, 리소스, 필터 등)이나 메시지 key가 바뀌면 kubernetes client만 새로 만들어 교체
+// 새 client를 하나라도 만들지 못하면 아무것도 적용하지 않고 기존 설정 유지
+// leader election, 집계, resume, metrics 서버 주소는 재시작해야 적용됨
+func (app *Application) Reload(next *config.Config) error {
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+	if app.stopping.Load() {
+		return nil
+	}
+	prev := app.config
+
+	// 교체할 client를 먼저 모두 생성
+	kp := app.kp.Producer()
+	var err error
+	if producerChanged(prev, next) {
+		if kp, err = newProducer(next); err != nil {
+			return err
+		}
+	}
+	var clusters []*kubeCluster
+	if clustersChanged(prev, next) {
+		if clusters, err = newClusters(next, app.kp); err != nil {
+			if kp != app.kp.Producer() {
+				kp.Close()
+			}
+			return err
+		}
+		app.setHandlers(clusters, next)
+	}
+
+	if kp != app.kp.Producer() {
+		// 새 producer로 전송하도록 교체한 후 이전 producer의 전송 결과를 기다려서 종료
+		go kp.Run()
+		old := app.kp.swap(kp)
+		old.Close()
+		logger.Info("reloaded kafka producer", zap.Strings("broker", next.Kafka.Broker), zap.String("topic", next.Kafka.Topic))
+	}
+	if clusters != nil {
+		app.replaceClusters(clusters)
+		logger.Info("reloaded kubernetes clients", zap.Int("clusters", len(clusters)))
+	}
+
+	if !reflect.DeepEqual(prev.LeaderElection, next.LeaderElection) {
+		logger.Warn("config leaderElection requires restart")
+	}
+	if prev.Aggregation != next.Aggregation {
+		logger.Warn("config aggregation requires restart")
+	}
+	if prev.Resume != next.Resume {
+		logger.Warn("config resume requires restart")
+	}
+	if prev.Server != next.Server {
+		logger.Warn("config server requires restart")
+	}
+
+	app.config = next
+	return nil
+}
+
+// replaceClusters 이전 cluster의 informer를 종료하고 새 cluster로 교체
+// informer를 실행 중이면(leader) 새 informer를 바로 실행하고, 최초 LIST로 받은 리소스 중 이미 전송한 것은 제외
+func (app *Application) replaceClusters(clusters []*kubeCluster) {
+	app.clustersMu.Lock()
+	defer app.clustersMu.Unlock()
+
+	for _, c := range app.clusters {
+		c.client.Close()
+	}
+	app.clusters = clusters
+	if app.informing {
+		for _, c := range clusters {
+			go c.client.Run()
+		}
+	}
+}
+
+// producerChanged kafka producer 설정 변경 여부 (메시지 key 제외)
+func producerChanged(prev, next *config.Config) bool {
+	a, b := prev.Kafka, next.Kafka
+	a.Key, b.Key = "", ""
+	return !reflect.DeepEqual(a, b)
+}
+
+// clustersChanged kubernetes client, handler 설정 변경 여부
+func clustersChanged(prev, next *config.Config) bool {
+	return !reflect.DeepEqual(prev.Kube, next.Kube) || prev.Kafka.Key != next.Kafka.Key
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"example.com/stradvision-project/cmd/client/config"
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// newResume resume 설정이 있으면 전송 기록 생성 (없으면 nil)
+func newResume(config *config.Config, kc *kube.Client) *kube.Resume {
+	var store kube.ResumeStore
+	switch {
+	case config.Resume.File != "":
+		store = kube.NewFileStore(config.Resume.File)
+	case config.Resume.ConfigMap != "":
+		store = kube.NewConfigMapStore(kc.Clientset(), config.Resume.Namespace, config.Resume.ConfigMap)
+	default:
+		return nil
+	}
+
+	return kube.NewResume(store,
+		kube.WithResumeMaxSize(config.Resume.MaxSize),
+		kube.WithResumeSaveInterval(config.Resume.SaveInterval),
+		kube.WithResumeErrorFunc(func(err error) {
+			logger.Error("failed to save resume", zap.Error(err))
+		}),
+	)
+}
+
+// runInformer 전송 기록을 읽은 뒤 cluster별 informer 실행
+// 기록을 읽지 못하면 중복 전송될 수 있지만 수집은 계속 진행
+func (app *Application) runInformer() {
+	if app.resume != nil {
+		if err := app.resume.Load(); err != nil {
+			logger.Error("failed to load resume", zap.Error(err))
+		} else {
+			logger.Info("loaded resume", zap.Int("count", app.resume.Len()))
+		}
+		go app.resume.Run()
+	}
+
+	// cluster마다 따로 실행하여 연결할 수 없는 cluster가 다른 cluster의 수집을 막지 않도록 함
+	// 연결이 끊기면 informer가 cluster별로 다시 연결
+	app.clustersMu.Lock()
+	defer app.clustersMu.Unlock()
+	app.informing = true
+	for _, c := range app.clusters {
+		go c.client.Run()
+	}
+}
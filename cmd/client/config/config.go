@@ -1,135 +1,178 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	pkgconfig "example.com/stradvision-project/pkg/config"
+	"example.com/stradvision-project/pkg/kafka/security"
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/logger"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
-const (
-	// Kubernetes 설정 환경변수
-	EnvKubeConfig string = "KUBECONFIG"
-	EnvResyncTime string = "RESYNC_TIME"
-
-	// Kafka 설정 환경변수
-	EnvKafkaBroker       string = "KAFKA_BROKER"
-	EnvKafkaTopic        string = "KAFKA_TOPIC"
-	EnvKafkaTimeout      string = "KAFKA_TIMEOUT"
-	EnvKafkaRetry        string = "KAFKA_RETRY"
-	EnvKafkaRetryBackoff string = "KAFKA_RETRY_BACKOFF"
-	EnvKafkaFlushMsg     string = "KAFKA_FLUSH_MSG"
-	EnvKafkaFlushSec     string = "KAFKA_FLUSH_SEC"
-	EnvKafkaFlushByte    string = "KAFKA_FLUSH_BYTE"
-)
+// Cluster 수집할 cluster 설정
+type Cluster struct {
+	Name    string `yaml:"name"`    // 필수, 문서의 cluster 필드와 kafka header에 사용
+	Config  string `yaml:"config"`  // kubeconfig 파일 (config, context 모두 없으면 in-cluster 자동 설정)
+	Context string `yaml:"context"` // kubeconfig context (없으면 current-context)
+}
 
 type Config struct {
 	Kube struct {
-		Config string        `yaml:"config"` // 없으면 in-cluster 자동 설정
-		Resync time.Duration `yaml:"resync"`
+		Config  string        `yaml:"config" env:"KUBECONFIG"`    // 없으면 in-cluster 자동 설정
+		Context string        `yaml:"context" env:"KUBE_CONTEXT"` // kubeconfig context (없으면 current-context)
+		Cluster string        `yaml:"cluster" env:"KUBE_CLUSTER"` // cluster 이름 (없으면 문서에 cluster 필드를 추가하지 않음)
+		Resync  time.Duration `yaml:"resync" env:"RESYNC_TIME"`
+
+		// 여러 cluster에서 수집하는 경우 설정 (없으면 config, context, cluster로 하나의 cluster에서 수집)
+		// leader election, resume ConfigMap은 첫 번째 cluster를 사용
+		Clusters []Cluster `yaml:"clusters"`
+
+		// 수집할 리소스 (group/version/resource, core 그룹은 version/resource)
+		// 없으면 events.k8s.io/v1/events
+		// ex) events.k8s.io/v1/events, v1/pods, apps/v1/deployments, kafka.strimzi.io/v1beta2/kafkatopics
+		Resources []string `yaml:"resources" env:"KUBE_RESOURCES"`
+
+		// 수집 대상 필터
+		Namespaces        []string `yaml:"namespaces" env:"KUBE_NAMESPACES"`                // 없으면 모든 namespace
+		ExcludeNamespaces []string `yaml:"excludeNamespaces" env:"KUBE_EXCLUDE_NAMESPACES"` // ex) kube-system
+		FieldSelector     string   `yaml:"fieldSelector" env:"KUBE_FIELD_SELECTOR"`         // 이벤트에만 적용, ex) type=Warning,regarding.kind=Pod
+		LabelSelector     string   `yaml:"labelSelector" env:"KUBE_LABEL_SELECTOR"`         // 이벤트는 대상 리소스의 label에 적용, ex) app=nginx
+
+		// 삭제된 이벤트 중 전송하지 않은 이벤트의 마지막 상태와 그 외 리소스의 삭제 기록(operation: delete) 전송
+		HandleDelete bool `yaml:"handleDelete" env:"KUBE_HANDLE_DELETE"`
+		// kube-apiserver --event-ttl, 삭제된 이벤트의 전송 여부를 판단하기 위해 전송한 이벤트를 이 시간 동안 기록
+		EventTTL time.Duration `yaml:"eventTTL" env:"KUBE_EVENT_TTL" default:"1h"`
+
+		// 이벤트 대상 리소스(Pod, ReplicaSet, Node)의 owner chain, node, label, annotation, container image 추가
+		Enrich struct {
+			Enabled     bool     `yaml:"enabled" env:"ENABLED"`
+			Labels      []string `yaml:"labels" env:"LABELS"`           // 추가할 label key (없으면 모든 label)
+			Annotations []string `yaml:"annotations" env:"ANNOTATIONS"` // 추가할 annotation key (없으면 추가하지 않음)
+		} `yaml:"enrich" env:"KUBE_ENRICH"`
 	} `yaml:"kube"`
 
 	Kafka struct {
-		Broker       []string      `yaml:"broker"` // 필수
-		Topic        string        `yaml:"topic"`  // 필수
-		Timeout      time.Duration `yaml:"timeout"`
-		Retry        int           `yaml:"retry"`
-		RetryBackoff time.Duration `yaml:"retryBackoff"`
-		FlushMsg     int           `yaml:"flushMsg"`
-		FlushTime    time.Duration `yaml:"flushTime"`
-		FlushByte    int           `yaml:"flushByte"`
+		Broker       []string      `yaml:"broker" env:"KAFKA_BROKER" required:"true"`
+		Topic        string        `yaml:"topic" env:"KAFKA_TOPIC" required:"true"`
+		Timeout      time.Duration `yaml:"timeout" env:"KAFKA_TIMEOUT"`
+		Retry        int           `yaml:"retry" env:"KAFKA_RETRY"`
+		RetryBackoff time.Duration `yaml:"retryBackoff" env:"KAFKA_RETRY_BACKOFF"`
+		FlushMsg     int           `yaml:"flushMsg" env:"KAFKA_FLUSH_MSG"`
+		FlushTime    time.Duration `yaml:"flushTime" env:"KAFKA_FLUSH_SEC"`
+		FlushByte    int           `yaml:"flushByte" env:"KAFKA_FLUSH_BYTE"`
+		Key          string        `yaml:"key" env:"KAFKA_KEY" default:"involved"` // 메시지 key 생성 방식: involved, uid, none
+		// 보안 설정 (없으면 PLAINTEXT, 인증하지 않음)
+		TLS  security.TLS  `yaml:"tls" env:"KAFKA_TLS"`
+		SASL security.SASL `yaml:"sasl" env:"KAFKA_SASL"`
 	} `yaml:"kafka"`
+
+	// 여러 replica 중 leader 하나만 이벤트를 수집 (coordination.k8s.io Lease)
+	LeaderElection struct {
+		Enabled       bool          `yaml:"enabled" env:"ENABLED"`
+		Namespace     string        `yaml:"namespace" env:"NAMESPACE"`                        // lease namespace (enabled면 필수)
+		Name          string        `yaml:"name" env:"NAME" default:"stradvision-client"`     // lease 이름
+		Identity      string        `yaml:"identity" env:"IDENTITY"`                          // replica 식별자 (기본값 hostname)
+		LeaseDuration time.Duration `yaml:"leaseDuration" env:"LEASE_DURATION" default:"15s"` // standby가 leader를 가져가기까지의 시간
+		RenewDeadline time.Duration `yaml:"renewDeadline" env:"RENEW_DEADLINE" default:"10s"`
+		RetryPeriod   time.Duration `yaml:"retryPeriod" env:"RETRY_PERIOD" default:"2s"`
+	} `yaml:"leaderElection" env:"LEADER_ELECTION"`
+
+	// 같은 대상(regarding.uid)과 reason으로 반복된 이벤트를 window 동안 하나의 메시지로 집계
+	Aggregation struct {
+		Window time.Duration `yaml:"window" env:"AGGREGATION_WINDOW"` // 없으면 집계하지 않음, ex) 1m
+	} `yaml:"aggregation"`
+
+	// 전송한 리소스의 UID별 resourceVersion을 기록하여 재시작 후 최초 LIST에서 이미 전송한 리소스 제외
+	// file, configMap 중 하나만 설정 (둘 다 없으면 사용하지 않음)
+	Resume struct {
+		File         string        `yaml:"file" env:"FILE"`                        // 로컬 파일 경로
+		ConfigMap    string        `yaml:"configMap" env:"CONFIGMAP"`              // ConfigMap 이름 (leader election 사용 시 replica 간 공유)
+		Namespace    string        `yaml:"namespace" env:"NAMESPACE"`              // ConfigMap namespace (configMap이면 필수)
+		MaxSize      int           `yaml:"maxSize" env:"MAX_SIZE" default:"10000"` // 기록할 최대 UID 개수
+		SaveInterval time.Duration `yaml:"saveInterval" env:"SAVE_INTERVAL" default:"10s"`
+	} `yaml:"resume" env:"RESUME"`
+
+	// 로그 설정 (설정 reload 시 바로 적용)
+	Log struct {
+		Level string `yaml:"level" env:"LOG_LEVEL"` // debug, info(기본값), warn, error
+	} `yaml:"log"`
+
+	// metrics http 서버 설정
+	Server struct {
+		Address string `yaml:"address" env:"SERVER_ADDRESS" default:":8080"`
+	} `yaml:"server"`
 }
 
-// LoadConfig 설정 파일을 읽어서 Config 구조체로 반환
-func LoadConfig(filename string) (*Config, error) {
+// LoadConfig 설정 파일, 환경변수, command-line flag를 읽어서 Config 구조체로 반환
+// 검증 에러는 모두 모아서 반환
+func LoadConfig(loader *pkgconfig.Loader) (*Config, pkgconfig.Sources, error) {
 	config := &Config{}
-	if err := readFile(filename, config); err != nil {
-		return nil, fmt.Errorf("failed config read file: %w", err)
-	}
-	readEnv(config)
-	ShowConfig(config)
-
-	return config, checkConfig(config)
+	sources, err := loader.Load(config, func() error { return checkConfig(config) })
+	return config, sources, err
 }
 
-// checkConfig 필수 설정 값이 있는지 확인
+// checkConfig 설정 값 확인 (필수 값은 required 태그로 확인)
 func checkConfig(config *Config) error {
-	if config.Kafka.Broker == nil || len(config.Kafka.Broker) == 0 {
-		return fmt.Errorf("config kafka broker required")
-	}
-	if config.Kafka.Topic == "" {
-		return fmt.Errorf("config kafka topic required")
-	}
-
-	return nil
-}
+	errs := make([]error, 0)
 
-// readFile 설정 파일을 읽어서 Config 구조체로 반환
-func readFile(filename string, config *Config) error {
-	file, err := os.ReadFile(filename)
-	if err != nil {
-		return err
+	if err := config.Kafka.TLS.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("config kafka %w", err))
 	}
-
-	if err := yaml.Unmarshal(file, config); err != nil {
-		return err
+	if err := config.Kafka.SASL.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("config kafka %w", err))
 	}
-
-	return nil
-}
-
-// readEnv 환경변수를 읽어서 Config 구조체에 설정
-func readEnv(config *Config) {
-	// kubernetes client 설정
-	if env := os.Getenv(EnvKubeConfig); env != "" {
-		config.Kube.Config = env
+	switch config.Kafka.Key {
+	case "", kube.MessageKeyInvolved, kube.MessageKeyUID, kube.MessageKeyNone:
+	default:
+		errs = append(errs, fmt.Errorf("config kafka key invalid: %s", config.Kafka.Key))
 	}
-	if env := os.Getenv(EnvResyncTime); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kube.Resync = value
+
+	// clusters
+	names := make(map[string]bool)
+	for i, cluster := range config.Kube.Clusters {
+		if cluster.Name == "" {
+			errs = append(errs, fmt.Errorf("config kube clusters[%d] name required", i))
+			continue
+		}
+		if names[cluster.Name] {
+			errs = append(errs, fmt.Errorf("config kube clusters name duplicated: %s", cluster.Name))
 		}
+		names[cluster.Name] = true
 	}
 
-	// kafka producer 설정
-	if env := os.Getenv(EnvKafkaBroker); env != "" {
-		brokers := strings.Split(env, ",")
-		config.Kafka.Broker = brokers
+	if _, err := kube.ParseResources(config.Kube.Resources); err != nil {
+		errs = append(errs, fmt.Errorf("config kube resources invalid: %w", err))
 	}
-	if env := os.Getenv(EnvKafkaTopic); env != "" {
-		config.Kafka.Topic = env
+	if _, err := fields.ParseSelector(config.Kube.FieldSelector); err != nil {
+		errs = append(errs, fmt.Errorf("config kube fieldSelector invalid: %w", err))
 	}
-	if env := os.Getenv(EnvKafkaTimeout); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.Timeout = value
-		}
+	if _, err := labels.Parse(config.Kube.LabelSelector); err != nil {
+		errs = append(errs, fmt.Errorf("config kube labelSelector invalid: %w", err))
 	}
-	if env := os.Getenv(EnvKafkaRetry); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.Retry = value
-		}
+	if config.Kube.HandleDelete && config.Kube.EventTTL <= 0 {
+		errs = append(errs, fmt.Errorf("config kube eventTTL must be positive: %s", config.Kube.EventTTL))
 	}
-	if env := os.Getenv(EnvKafkaRetryBackoff); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.RetryBackoff = value
-		}
+
+	// leader election
+	if config.LeaderElection.Enabled && config.LeaderElection.Namespace == "" {
+		errs = append(errs, fmt.Errorf("config leaderElection namespace required"))
 	}
-	if env := os.Getenv(EnvKafkaFlushMsg); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.FlushMsg = value
-		}
+
+	// resume
+	if config.Resume.File != "" && config.Resume.ConfigMap != "" {
+		errs = append(errs, fmt.Errorf("config resume file and configMap are exclusive"))
 	}
-	if env := os.Getenv(EnvKafkaFlushSec); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.FlushTime = value
-		}
+	if config.Resume.ConfigMap != "" && config.Resume.Namespace == "" {
+		errs = append(errs, fmt.Errorf("config resume namespace required"))
 	}
-	if env := os.Getenv(EnvKafkaFlushByte); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.FlushByte = value
-		}
+
+	if _, err := logger.ParseLevel(config.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("config log %w", err))
 	}
+
+	return errors.Join(errs...)
 }
@@ -5,12 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	pkgconfig "example.com/stradvision-project/pkg/config"
 )
 
 func TestConfig(t *testing.T) {
 	path, _ := os.Getwd()
 
-	config, err := LoadConfig(filepath.Join(path, "test.yaml"))
+	loader, err := pkgconfig.NewLoader("client", &Config{}, []string{"--config", filepath.Join(path, "test.yaml")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, _, err := LoadConfig(loader)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1,18 +1,11 @@
 package config
 
 import (
-	"example.com/stradvision-project/pkg/logger"
-	"go.uber.org/zap"
+	pkgconfig "example.com/stradvision-project/pkg/config"
 )
 
-func ShowConfig(config *Config) {
-	logger.Debug("kubernetes",
-		zap.String("config", config.Kube.Config),
-		zap.Duration("resync", config.Kube.Resync),
-	)
-
-	logger.Debug("kafka",
-		zap.Strings("broker", config.Kafka.Broker),
-		zap.String("topic", config.Kafka.Topic),
-	)
+// ShowConfig 전체 설정을 출처(file, env, default)와 함께 출력
+// secret 필드(password, API key, token 등)는 가림
+func ShowConfig(config *Config, sources pkgconfig.Sources) {
+	pkgconfig.Print(config, sources)
 }
@@ -1,10 +1,14 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"example.com/stradvision-project/cmd/consumer/config"
 	"example.com/stradvision-project/pkg/es"
@@ -12,35 +16,128 @@ import (
 	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/kube"
 	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/metrics"
+	"go.uber.org/zap"
 )
 
-type Application struct {
-	// kafka
-	kc    *consumer.KafkaConsumer
-	dlpKp *producer.KafkaProducer
+const (
+	// DefaultShutdownTimeout 종료 시 남은 이벤트 처리를 기다리는 최대 시간
+	// kubernetes terminationGracePeriodSeconds(30초)보다 짧아야 함
+	DefaultShutdownTimeout = 25 * time.Second
+)
 
-	// elasticsearch
-	index string
-	ec    *es.Client
+type Application struct {
+	// 설정 reload 시 교체되는 컴포넌트
+	kc     atomic.Pointer[consumer.KafkaConsumer]
+	dlq    atomic.Pointer[dlqProducer]
+	ec     atomic.Pointer[es.Client]
+	target atomic.Pointer[target]
 
 	// data buffer
 	buf *kube.EventBuffer
+
+	// metrics
+	server *metrics.Server
+
+	// 종료 시간을 초과하면 취소하여 elasticsearch 재시도 대기 중단
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	consumeErr chan error     // kafka consumer 종료, dlq 전송 실패 등 복구할 수 없는 에러 (application 종료)
+	config     *config.Config // 마지막으로 적용한 설정
+	reloadMu   sync.Mutex     // Reload, shutdown 동시 실행 방지
+	stopping   bool
+}
+
+// dlqProducer 저장에 실패한 이벤트를 전송할 kafka producer
+type dlqProducer struct {
+	kp    *producer.KafkaProducer
+	topic string
+}
+
+// target 문서 저장 설정
+type target struct {
+	index      string
+	action     string
+	documentID string
 }
 
 func NewApplication(config *config.Config) (*Application, error) {
-	app := &Application{}
+	app := &Application{consumeErr: make(chan error, 1), config: config}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
 
 	// elasticsearch client
+	ec, err := newESClient(config)
+	if err != nil {
+		return nil, err
+	}
+	app.ec.Store(ec)
+	app.target.Store(newTarget(config))
+
+	// kafka dlq producer
+	dlq, err := newDLQProducer(config)
+	if err != nil {
+		return nil, err
+	}
+	app.dlq.Store(dlq)
+
+	// kafka consumer
+	kc, err := app.newConsumer(config)
+	if err != nil {
+		return nil, err
+	}
+	app.kc.Store(kc)
+
+	// data buffer
+	buf, err := kube.NewEventBuffer(app.bufferDo, app.bufferErrHandler, bufferOptions(config)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event buffer: %w", err)
+	}
+	app.buf = buf
+
+	// metrics http server
+	app.server = metrics.NewServer(config.Server.Address)
+	app.registerHealth()
+
+	return app, nil
+}
+
+// newESClient elasticsearch client 생성
+func newESClient(config *config.Config) (*es.Client, error) {
 	ec, err := es.NewElasticsearchClient(
 		config.ElasticSearch.Addresses, config.ElasticSearch.User, config.ElasticSearch.Pass,
+		es.WithRetryMax(config.ElasticSearch.RetryMax),
+		es.WithRetryBackoff(config.ElasticSearch.RetryBackoff),
+		es.WithRetryMaxBackoff(config.ElasticSearch.RetryMaxBackoff),
+		es.WithRetryJitter(config.ElasticSearch.RetryJitter),
+		es.WithCloudID(config.ElasticSearch.CloudID),
+		es.WithPasswordFile(config.ElasticSearch.PassFile),
+		es.WithAPIKey(config.ElasticSearch.APIKey),
+		es.WithAPIKeyFile(config.ElasticSearch.APIKeyFile),
+		es.WithServiceToken(config.ElasticSearch.ServiceToken),
+		es.WithServiceTokenFile(config.ElasticSearch.ServiceTokenFile),
+		es.WithCACertFile(config.ElasticSearch.CACert),
+		es.WithCertificateFingerprint(config.ElasticSearch.CertificateFingerprint),
+		es.WithClientCertFile(config.ElasticSearch.ClientCert, config.ElasticSearch.ClientKey),
+		es.WithInsecureSkipVerify(config.ElasticSearch.InsecureSkipVerify),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create elasticsearch client: %w", err)
 	}
-	app.ec = ec
-	app.index = config.ElasticSearch.Index
+	return ec, nil
+}
 
-	// kafka dlq producer
+// newTarget 문서 저장 설정
+func newTarget(config *config.Config) *target {
+	return &target{
+		index:      config.ElasticSearch.Index,
+		action:     config.ElasticSearch.Action,
+		documentID: config.ElasticSearch.DocumentID,
+	}
+}
+
+// newDLQProducer kafka dlq producer 생성
+func newDLQProducer(config *config.Config) (*dlqProducer, error) {
 	kp, err := producer.NewKafkaProducer(
 		config.Kafka.Broker, config.Kafka.DlqTopic,
 		producer.WithTimeout(config.Kafka.Timeout),
@@ -49,53 +146,131 @@ func NewApplication(config *config.Config) (*Application, error) {
 		producer.WithFlushMaxMessages(config.Kafka.FlushMsg),
 		producer.WithFlushFrequency(config.Kafka.FlushTime),
 		producer.WithFlushBytes(config.Kafka.FlushByte),
+		producer.WithTLS(config.Kafka.TLS),
+		producer.WithSASL(config.Kafka.SASL),
 		producer.WithErrorFunc(ProducerErrorHandler),
 		producer.WithSuccessFunc(ProducerSuccessHandler),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka producer dlq: %w", err)
 	}
-	app.dlpKp = kp
+	return &dlqProducer{kp: kp, topic: config.Kafka.DlqTopic}, nil
+}
 
-	// kafka consumer
+// newConsumer kafka consumer 생성
+func (app *Application) newConsumer(config *config.Config) (*consumer.KafkaConsumer, error) {
 	kc, err := consumer.NewKafkaConsumer(
 		config.Kafka.Broker, config.Kafka.GroupID, config.Kafka.Topic,
 		consumer.WithErrFunc(ConsumerErrorHandler),
 		consumer.WithDoFunc(app.ConsumerDo),
 		consumer.WithBalanceStrategy(config.Kafka.RebalanceStrategy),
+		consumer.WithTLS(config.Kafka.TLS),
+		consumer.WithSASL(config.Kafka.SASL),
+		consumer.WithCommitInterval(config.Kafka.CommitInterval),
+		consumer.WithAckTimeout(config.Kafka.AckTimeout),
+		consumer.WithConsumeRetryMax(config.Kafka.ConsumeRetryMax),
+		consumer.WithConsumeRetryBackoff(config.Kafka.ConsumeRetryBackoff),
+		consumer.WithConsumeRetryMaxBackoff(config.Kafka.ConsumeRetryMaxBackoff),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
 	}
-	app.kc = kc
+	return kc, nil
+}
 
-	// data buffer
-	buf, err := kube.NewEventBuffer(app.bufferDo, app.bufferErrHandler)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create event buffer: %w", err)
+// bufferOptions event buffer 설정
+func bufferOptions(config *config.Config) []kube.BufferOption {
+	return []kube.BufferOption{
+		kube.WithFlushMaxCount(config.Buffer.FlushMaxCount),
+		kube.WithFlushMaxBytes(config.Buffer.FlushMaxBytes),
+		kube.WithFlushInterval(config.Buffer.FlushInterval),
+		kube.WithQueueSize(config.Buffer.QueueSize),
+		kube.WithDropWhenFull(config.Buffer.DropWhenFull),
 	}
-	app.buf = buf
-
-	return app, nil
 }
 
-func (app *Application) Run() {
+// Run application 실행
+// kafka consumer가 복구할 수 없는 에러로 종료되면 정리 후 에러를 반환
+func (app *Application) Run() error {
 	logger.Info("start consumer application ...")
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go app.buf.Run()
-	go app.dlpKp.Run()
-	go app.kc.Run()
+	go app.dlq.Load().kp.Run()
+	go app.runConsumer(app.kc.Load())
+	go app.runServer()
+
+	var runErr error
+	select {
+	case <-sigChan:
+	case runErr = <-app.consumeErr:
+		logger.Error("failed to run consumer application", zap.Error(runErr))
+	}
 
-	<-sigChan
-	app.buf.Close()
-	logger.Info("close buffer ...")
-	app.kc.Close()
-	logger.Info("close consumer ...")
-	app.dlpKp.Close()
-	logger.Info("close dlq producer ...")
+	app.shutdown()
 
 	logger.Info("stop consumer application ...")
+	return runErr
+}
+
+// runConsumer kafka consumer 실행
+// reload로 교체되어 종료된 consumer의 결과는 무시
+func (app *Application) runConsumer(kc *consumer.KafkaConsumer) {
+	err := kc.Run()
+	if err == nil || app.kc.Load() != kc {
+		return
+	}
+	app.fail(err)
+}
+
+// fail 복구할 수 없는 에러로 application 종료
+// commit되지 않은 메시지는 재시작 후 다시 수신
+func (app *Application) fail(err error) {
+	select {
+	case app.consumeErr <- err:
+	default:
+	}
+}
+
+// shutdown 수신 중지, buffer flush, dlq 전송 결과 대기, offset commit 순서로 종료
+// DefaultShutdownTimeout 안에 끝나지 않으면 기다리지 않고 종료 (commit되지 않은 메시지는 다시 수신됨)
+func (app *Application) shutdown() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// 진행 중인 reload가 끝난 후 종료
+		app.reloadMu.Lock()
+		app.stopping = true
+		app.reloadMu.Unlock()
+
+		kc := app.kc.Load()
+		kc.Pause()
+		logger.Info("pause consumer ...")
+		app.buf.Close()
+		logger.Info("close buffer ...")
+		app.dlq.Load().kp.Close()
+		logger.Info("close dlq producer ...")
+		kc.Close()
+		logger.Info("close consumer ...")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(DefaultShutdownTimeout):
+		logger.Error("shutdown timed out", zap.Duration("timeout", DefaultShutdownTimeout))
+	}
+	app.cancel()
+
+	app.server.Close()
+	logger.Info("close metrics server ...")
+}
+
+// runServer metrics http 서버 실행
+func (app *Application) runServer() {
+	if err := app.server.Run(); err != nil {
+		logger.Error("failed to run metrics server", zap.Error(err))
+	}
 }
@@ -1,56 +1,165 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"example.com/stradvision-project/pkg/es"
+	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/kube"
 	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/metrics"
 	"go.uber.org/zap"
 )
 
 const (
-	template = `{"index": {"_index": "%s" } }`
+	// DefaultDLQTimeout dlq 전송 결과를 기다리는 최대 시간 (재시도 포함)
+	// buffer 루프가 멈추므로 DefaultBufferAliveTimeout보다 짧아야 함
+	DefaultDLQTimeout = 30 * time.Second
+	// DefaultDLQRetry dlq 전송 실패 시 최대 시도 횟수
+	DefaultDLQRetry = 3
+	// DefaultDLQRetryBackoff dlq 재전송 간격
+	DefaultDLQRetryBackoff = time.Second
 )
 
-func (app *Application) bufferDo(events []*kube.Event) error {
+// bulkFailedError 재시도 후에도 bulk 요청에 실패한 문서가 있는 경우의 에러
+type bulkFailedError struct {
+	items  []es.BulkItem
+	events []kube.Document
+}
+
+func (e *bulkFailedError) Error() string {
+	return fmt.Sprintf("elasticsearch bulk request failed: %d items", len(e.items))
+}
+
+func (app *Application) bufferDo(events []kube.Document) error {
 	// elasticsearch flush
-	result := make([]byte, 0)
+	// reload로 설정이 바뀌어도 같은 flush의 이벤트는 같은 index에 저장
+	t := app.target.Load()
+	items := make([][]byte, 0, len(events))
 
 	for _, event := range events {
-		data, err := json.Marshal(event)
+		item, err := convertEvent(t, event)
 		if err != nil {
 			return fmt.Errorf("failed bufferDo marshal event: %w", err)
 		}
+		items = append(items, item)
+	}
 
-		meta := []byte(fmt.Sprintf(template, app.index))
-		result = append(result, meta...)
-		result = append(result, '\n')
-		result = append(result, data...)
-		result = append(result, '\n')
+	logger.Debug("bufferDo", zap.Int("count", len(items)))
+	// 재시도 가능한 실패는 재전송하고, 재시도 후에도 실패한 문서만 골라서 에러로 반환
+	bulkResult := app.ec.Load().WriteBulkWithRetry(app.ctx, t.index, items)
+	// 저장에 성공한 이벤트는 ack 처리하여 offset commit
+	for _, item := range bulkResult.Succeeded {
+		if item.Position < len(events) {
+			events[item.Position].Ack()
+		}
 	}
 
-	result = append(result, '\n')
-	logger.Debug("bufferDo", zap.String("result", string(result)))
-	if err := app.ec.WriteBulk(app.index, result); err != nil {
-		return fmt.Errorf("failed bufferDo send message: %w", err)
+	if bulkResult.HasFailed() {
+		failed := make([]kube.Document, 0, len(bulkResult.Failed))
+		for _, position := range bulkResult.FailedPositions() {
+			if position < len(events) {
+				failed = append(failed, events[position])
+			}
+		}
+
+		return &bulkFailedError{items: bulkResult.Failed, events: failed}
 	}
 
 	return nil
 }
 
-func (app *Application) bufferErrHandler(err error, events []*kube.Event) {
+// convertEvent 이벤트를 bulk 요청 문서로 변환
+// 문서 ID를 이벤트로부터 생성하여 같은 이벤트를 다시 처리해도 중복 저장되지 않도록 함
+// Event 이외의 리소스는 kind별 index에 저장 (ex. event-object-pod)
+func convertEvent(t *target, event kube.Document) ([]byte, error) {
+	return es.ConvertAction(es.BulkAction{
+		Action:  t.action,
+		Index:   kube.IndexName(t.index, event),
+		ID:      event.DocumentID(t.documentID),
+		Version: event.Version(),
+	}, event)
+}
+
+func (app *Application) bufferErrHandler(err error, events []kube.Document) {
 	// log error
 	logger.Error("failed to flush events", zap.Error(err))
 
+	// bulk 요청에 실패한 경우 실패한 문서만 dlq로 전송
+	var bulkErr *bulkFailedError
+	if errors.As(err, &bulkErr) {
+		for _, item := range bulkErr.items {
+			logger.Error("failed bulk item",
+				zap.Int("position", item.Position),
+				zap.Int("status", item.Status),
+				zap.String("type", item.ErrorType),
+				zap.String("reason", item.ErrorReason),
+			)
+		}
+		events = bulkErr.events
+	}
+
 	// send to kafka dlq
+	t := app.target.Load()
+	dlq := app.dlq.Load()
+	// dlq 전송 결과를 기다려 성공한 이벤트만 ack 처리하여 offset commit
+	// 실패한 이벤트는 재전송하고, 그래도 실패하면 application을 종료하여 재시작 후 다시 수신
+	messages := make([]producer.Message, 0, len(events))
 	for _, event := range events {
 		data, err := json.Marshal(event)
 		if err != nil {
 			logger.Error("failed bufferErrHandler marshal event", zap.Error(err))
+			event.Ack()
 			continue
 		}
 
-		app.dlpKp.SendMessage(app.index, data)
+		messages = append(messages, producer.Message{
+			Key:      t.index,
+			Value:    data,
+			Metadata: event,
+		})
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDLQTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		failed := make([]producer.Message, 0)
+		for _, result := range dlq.kp.SendBatch(ctx, messages) {
+			event := result.Message.Metadata.(kube.Document)
+			if result.Err != nil {
+				logger.Error("failed to send event to dlq",
+					zap.String("id", event.DocumentID(t.documentID)),
+					zap.Int("attempt", attempt),
+					zap.Error(result.Err),
+				)
+				failed = append(failed, result.Message)
+				continue
+			}
+
+			event.Ack()
+			metrics.DLQSent.WithLabelValues(dlq.topic).Inc()
+		}
+		if len(failed) == 0 {
+			return
+		}
+
+		if attempt >= DefaultDLQRetry || ctx.Err() != nil {
+			// 시간 초과된 메시지는 나중에 전송될 수 있으므로 재시작 후 dlq에 중복 저장될 수 있음 (문서 ID로 중복 제거)
+			app.fail(fmt.Errorf("failed to send %d events to dlq", len(failed)))
+			return
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(DefaultDLQRetryBackoff):
+		}
+		messages = failed
 	}
 }
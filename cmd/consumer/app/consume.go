@@ -1,21 +1,34 @@
 package app
 
 import (
-	"encoding/json"
+	"errors"
 
 	"example.com/stradvision-project/pkg/kube"
 	"example.com/stradvision-project/pkg/logger"
 	"go.uber.org/zap"
 )
 
-func (app *Application) ConsumerDo(data []byte) {
-	event := &kube.Event{}
-	if err := json.Unmarshal(data, event); err != nil {
+// ConsumerDo 수신한 메시지를 buffer에 추가
+// ack는 이벤트가 저장(또는 dlq 전송)된 후에 호출되어 offset이 commit 됨
+func (app *Application) ConsumerDo(data []byte, ack func()) {
+	event, err := kube.Decode(data)
+	if err != nil {
+		// 다시 처리해도 실패하는 메시지이므로 ack 처리
 		logger.Error("failed to consume unmarshal data", zap.Error(err))
+		ack()
 		return
 	}
 
-	app.buf.AddEvent(event)
+	event.SetAck(ack)
+	switch err := app.buf.AddEvent(event); {
+	case errors.Is(err, kube.ErrBufferFull):
+		// 버린 이벤트를 ack하지 않으면 이후 offset이 commit되지 않으므로 ack 처리
+		logger.Error("dropped event buffer full", zap.String("event", event.Meta().Name))
+		ack()
+	case err != nil:
+		// 종료 중에는 ack하지 않으므로 다시 시작하면 다시 수신됨
+		logger.Debug("failed to add event", zap.Error(err))
+	}
 }
 
 // Run application
@@ -1,18 +1,30 @@
 package app
 
 import (
-	"time"
-
+	"example.com/stradvision-project/pkg/kafka/producer"
 	"example.com/stradvision-project/pkg/logger"
 	"go.uber.org/zap"
 )
 
 // ProducerErrorHandler
-func ProducerErrorHandler(ts time.Time, topic string, partition int32, err error) {
-	logger.Error("failed dlq producer error", zap.Time("ts", ts), zap.String("topic", topic), zap.Int32("partition", partition), zap.Error(err))
+func ProducerErrorHandler(result *producer.Result) {
+	logger.Error("failed dlq producer error",
+		zap.Time("ts", result.Timestamp),
+		zap.String("topic", result.Topic),
+		zap.Int32("partition", result.Partition),
+		zap.String("key", result.Message.Key),
+		zap.Any("headers", result.Message.Headers),
+		zap.Error(result.Err),
+	)
 }
 
 // ProducerSuccessHandler
-func ProducerSuccessHandler(ts time.Time, topic string, partition int32) {
-	logger.Info("success dlq producer", zap.Time("ts", ts), zap.String("topic", topic), zap.Int32("partition", partition))
+func ProducerSuccessHandler(result *producer.Result) {
+	logger.Info("success dlq producer",
+		zap.Time("ts", result.Timestamp),
+		zap.String("topic", result.Topic),
+		zap.Int32("partition", result.Partition),
+		zap.Int64("offset", result.Offset),
+		zap.String("key", result.Message.Key),
+	)
 }
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"example.com/stradvision-project/pkg/health"
+)
+
+const (
+	// DefaultBufferAliveTimeout buffer 루프가 이 시간 이상 멈춰있으면 liveness 실패
+	DefaultBufferAliveTimeout = time.Minute
+)
+
+// registerHealth liveness, readiness 확인 함수를 등록하고 metrics 서버에 endpoint 추가
+func (app *Application) registerHealth() {
+	h := health.NewHealth()
+
+	h.AddLiveness("buffer", func() error {
+		return app.buf.Alive(DefaultBufferAliveTimeout)
+	})
+	h.AddLiveness("consumer", func() error {
+		if !app.kc.Load().IsRunning() {
+			return fmt.Errorf("kafka consumer is not running")
+		}
+		return nil
+	})
+
+	h.AddReadiness("consumer", func() error {
+		if !app.kc.Load().IsActive() {
+			return fmt.Errorf("kafka consumer group session is not active")
+		}
+		return nil
+	})
+	h.AddReadiness("dlq", func() error {
+		return app.dlq.Load().kp.Ping()
+	})
+	h.AddReadiness("elasticsearch", func() error {
+		return app.ec.Load().Ping()
+	})
+
+	app.server.Handle(health.LivenessPath, h.LivenessHandler())
+	app.server.Handle(health.ReadinessPath, h.ReadinessHandler())
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"reflect"
+
+	"example.com/stradvision-project/cmd/consumer/config"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Reload 변경된 설정을 실행 중에 적용
+// index, 문서 저장 방식, buffer 임계값은 바로 적용하고
+// elasticsearch, kafka 연결 설정(주소, 인증, TLS 등)이 바뀌면 해당 client만 새로 만들어 교체
+// 새 client를 하나라도 만들지 못하면 아무것도 적용하지 않고 기존 설정 유지
+// 수신 대기열 크기, metrics 서버 주소는 재시작해야 적용됨
+func (app *Application) Reload(next *config.Config) error {
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+	if app.stopping {
+		return nil
+	}
+	prev := app.config
+
+	// 교체할 client를 먼저 모두 생성
+	ec, kc, dlq := app.ec.Load(), app.kc.Load(), app.dlq.Load()
+	var err error
+	if esChanged(prev, next) {
+		if ec, err = newESClient(next); err != nil {
+			return err
+		}
+	}
+	if dlqChanged(prev, next) {
+		if dlq, err = newDLQProducer(next); err != nil {
+			return err
+		}
+	}
+	if consumerChanged(prev, next) {
+		if kc, err = app.newConsumer(next); err != nil {
+			if dlq != app.dlq.Load() {
+				dlq.kp.Close()
+			}
+			return err
+		}
+	}
+
+	if ec != app.ec.Load() {
+		app.ec.Store(ec)
+		logger.Info("reloaded elasticsearch client", zap.Strings("addresses", next.ElasticSearch.Addresses))
+	}
+	if t := newTarget(next); *t != *app.target.Load() {
+		app.target.Store(t)
+		logger.Info("reloaded elasticsearch target",
+			zap.String("index", t.index),
+			zap.String("action", t.action),
+			zap.String("documentID", t.documentID),
+		)
+	}
+	if bufferChanged(prev, next) {
+		app.buf.Update(bufferOptions(next)...)
+		logger.Info("reloaded event buffer")
+	}
+	if old := app.dlq.Load(); dlq != old {
+		app.dlq.Store(dlq)
+		go dlq.kp.Run()
+		// 이전 producer로 전송 중인 flush가 끝난 후 종료
+		app.buf.Flush()
+		old.kp.Close()
+		logger.Info("reloaded kafka dlq producer", zap.String("topic", dlq.topic))
+	}
+	if old := app.kc.Load(); kc != old {
+		// 수신을 멈추고 이미 수신한 이벤트를 처리하여 offset commit 후 교체
+		old.Pause()
+		app.buf.Flush()
+		old.Close()
+		app.kc.Store(kc)
+		go app.runConsumer(kc)
+		logger.Info("reloaded kafka consumer", zap.Strings("broker", next.Kafka.Broker), zap.String("topic", next.Kafka.Topic))
+	}
+
+	if prev.Buffer.QueueSize != next.Buffer.QueueSize {
+		logger.Warn("config buffer.queueSize requires restart")
+	}
+	if prev.Server != next.Server {
+		logger.Warn("config server requires restart")
+	}
+
+	app.config = next
+	return nil
+}
+
+// esChanged elasticsearch 연결 설정(주소, 인증, TLS, 재시도) 변경 여부
+func esChanged(prev, next *config.Config) bool {
+	a, b := prev.ElasticSearch, next.ElasticSearch
+	a.Index, a.Action, a.DocumentID = "", "", ""
+	b.Index, b.Action, b.DocumentID = "", "", ""
+	return !reflect.DeepEqual(a, b)
+}
+
+// bufferChanged event buffer 임계값 변경 여부 (수신 대기열 크기 제외)
+func bufferChanged(prev, next *config.Config) bool {
+	a, b := prev.Buffer, next.Buffer
+	a.QueueSize, b.QueueSize = 0, 0
+	return a != b
+}
+
+// consumerChanged kafka consumer 설정 변경 여부
+func consumerChanged(prev, next *config.Config) bool {
+	a, b := prev.Kafka, next.Kafka
+	return !reflect.DeepEqual(a.Broker, b.Broker) ||
+		a.GroupID != b.GroupID ||
+		a.Topic != b.Topic ||
+		a.RebalanceStrategy != b.RebalanceStrategy ||
+		a.CommitInterval != b.CommitInterval ||
+		a.AckTimeout != b.AckTimeout ||
+		a.ConsumeRetryMax != b.ConsumeRetryMax ||
+		a.ConsumeRetryBackoff != b.ConsumeRetryBackoff ||
+		a.ConsumeRetryMaxBackoff != b.ConsumeRetryMaxBackoff ||
+		a.TLS != b.TLS ||
+		a.SASL != b.SASL
+}
+
+// dlqChanged kafka dlq producer 설정 변경 여부
+func dlqChanged(prev, next *config.Config) bool {
+	a, b := prev.Kafka, next.Kafka
+	return !reflect.DeepEqual(a.Broker, b.Broker) ||
+		a.DlqTopic != b.DlqTopic ||
+		a.Timeout != b.Timeout ||
+		a.Retry != b.Retry ||
+		a.RetryBackoff != b.RetryBackoff ||
+		a.FlushMsg != b.FlushMsg ||
+		a.FlushTime != b.FlushTime ||
+		a.FlushByte != b.FlushByte ||
+		a.TLS != b.TLS ||
+		a.SASL != b.SASL
+}
@@ -1,174 +1,147 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-)
-
-const (
-	// Kafka 설정 환경변수
-	EnvKafkaBroker string = "KAFKA_BROKER"
-	// kafka consumer
-	EnvKafkaGoupID     string = "KAFKA_GROUP_ID"
-	EnvKafkaTopic      string = "KAFKA_TOPIC"
-	EnvKafkaRebanlance string = "KAFKA_REBALANCE"
-	// kafka producer
-	EnvKafkaDlqTopic     string = "KAFKA_DLQ_TOPIC"
-	EnvKafkaTimeout      string = "KAFKA_TIMEOUT"
-	EnvKafkaRetry        string = "KAFKA_RETRY"
-	EnvKafkaRetryBackoff string = "KAFKA_RETRY_BACKOFF"
-	EnvKafkaFlushMsg     string = "KAFKA_FLUSH_MSG"
-	EnvKafkaFlushSec     string = "KAFKA_FLUSH_SEC"
-	EnvKafkaFlushByte    string = "KAFKA_FLUSH_BYTE"
-
-	// Storage ElasticSearch 설정 환경변수
-	EnvElasticAddress string = "ELASTIC_ADDRESS"
-	EnvElasticUser    string = "ELASTIC_USER"
-	EnvElasticPass    string = "ELASTIC_PASS"
-	EnvElasticIndex   string = "ELASTIC_INDEX"
+	pkgconfig "example.com/stradvision-project/pkg/config"
+	"example.com/stradvision-project/pkg/es"
+	"example.com/stradvision-project/pkg/kafka/security"
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/logger"
 )
 
 type Config struct {
 	Kafka struct {
 		// kafka 공통 설정
-		Broker []string `yaml:"broker"` // 필수
+		Broker []string `yaml:"broker" env:"KAFKA_BROKER" required:"true"`
 
 		// Consumer 그룹 설정
-		GroupID           string `yaml:"groupID"` // 필수
-		Topic             string `yaml:"topic"`   // 필수
-		RebalanceStrategy string `yaml:"rebalanceStrategy"`
+		GroupID           string `yaml:"groupID" env:"KAFKA_GROUP_ID" required:"true"`
+		Topic             string `yaml:"topic" env:"KAFKA_TOPIC" required:"true"`
+		RebalanceStrategy string `yaml:"rebalanceStrategy" env:"KAFKA_REBALANCE"`
+
+		// 수신 설정 (없으면 기본값)
+		CommitInterval         time.Duration `yaml:"commitInterval" env:"KAFKA_COMMIT_INTERVAL"`                   // ack된 offset commit 주기 (기본값 1s)
+		AckTimeout             time.Duration `yaml:"ackTimeout" env:"KAFKA_ACK_TIMEOUT"`                           // 메시지 ack 최대 대기 시간, 넘으면 종료 (기본값 5m)
+		ConsumeRetryMax        int           `yaml:"consumeRetryMax" env:"KAFKA_CONSUME_RETRY_MAX"`                // Consume 연속 실패 최대 횟수, 넘으면 종료 (기본값 10)
+		ConsumeRetryBackoff    time.Duration `yaml:"consumeRetryBackoff" env:"KAFKA_CONSUME_RETRY_BACKOFF"`        // Consume 재시도 간격, 실패할 때마다 2배 (기본값 1s)
+		ConsumeRetryMaxBackoff time.Duration `yaml:"consumeRetryMaxBackoff" env:"KAFKA_CONSUME_RETRY_MAX_BACKOFF"` // Consume 최대 재시도 간격 (기본값 30s)
 
 		// Dead Letter Queue 설정
-		DlqTopic     string        `yaml:"dlqTopic"` // 필수
-		Timeout      time.Duration `yaml:"timeout"`
-		Retry        int           `yaml:"retry"`
-		RetryBackoff time.Duration `yaml:"retryBackoff"`
-		FlushMsg     int           `yaml:"flushMsg"`
-		FlushTime    time.Duration `yaml:"flushTime"`
-		FlushByte    int           `yaml:"flushByte"`
+		DlqTopic     string        `yaml:"dlqTopic" env:"KAFKA_DLQ_TOPIC" required:"true"`
+		Timeout      time.Duration `yaml:"timeout" env:"KAFKA_TIMEOUT"`
+		Retry        int           `yaml:"retry" env:"KAFKA_RETRY"`
+		RetryBackoff time.Duration `yaml:"retryBackoff" env:"KAFKA_RETRY_BACKOFF"`
+		FlushMsg     int           `yaml:"flushMsg" env:"KAFKA_FLUSH_MSG"`
+		FlushTime    time.Duration `yaml:"flushTime" env:"KAFKA_FLUSH_SEC"`
+		FlushByte    int           `yaml:"flushByte" env:"KAFKA_FLUSH_BYTE"`
+		// 보안 설정 (없으면 PLAINTEXT, 인증하지 않음)
+		TLS  security.TLS  `yaml:"tls" env:"KAFKA_TLS"`
+		SASL security.SASL `yaml:"sasl" env:"KAFKA_SASL"`
 	} `yaml:"kafka"`
 
 	ElasticSearch struct {
-		Addresses []string `yaml:"addresses"` // 필수
-		User      string   `yaml:"user"`      // 필수
-		Pass      string   `yaml:"pass"`      // 필수
-		Index     string   `yaml:"index"`     // 필수
+		Addresses []string `yaml:"addresses" env:"ELASTIC_ADDRESS"` // cloudID가 없으면 필수
+		User      string   `yaml:"user" env:"ELASTIC_USER"`         // basic 인증 (apiKey, serviceToken을 사용하지 않는 경우)
+		Pass      string   `yaml:"pass" env:"ELASTIC_PASS" secret:"true"`
+		Index     string   `yaml:"index" env:"ELASTIC_INDEX" required:"true"`
+		CloudID   string   `yaml:"cloudID" env:"ELASTIC_CLOUD_ID"` // 설정하면 addresses 대신 Elastic Cloud endpoint 사용
+
+		// 인증 (apiKey > serviceToken > user/pass 순서로 사용)
+		// *File: mount된 secret 파일에서 읽음 (값보다 우선)
+		PassFile         string `yaml:"passFile" env:"ELASTIC_PASS_FILE"`
+		APIKey           string `yaml:"apiKey" env:"ELASTIC_API_KEY" secret:"true"`
+		APIKeyFile       string `yaml:"apiKeyFile" env:"ELASTIC_API_KEY_FILE"`
+		ServiceToken     string `yaml:"serviceToken" env:"ELASTIC_SERVICE_TOKEN" secret:"true"` // bearer token
+		ServiceTokenFile string `yaml:"serviceTokenFile" env:"ELASTIC_SERVICE_TOKEN_FILE"`
+
+		// TLS (기본값은 시스템 CA로 서버 인증서 검증)
+		CACert                 string `yaml:"caCert" env:"ELASTIC_CA_CERT"`                          // CA 인증서 파일
+		CertificateFingerprint string `yaml:"certificateFingerprint" env:"ELASTIC_CERT_FINGERPRINT"` // CA 인증서 SHA-256 fingerprint (Elasticsearch 8 기본 설정)
+		ClientCert             string `yaml:"clientCert" env:"ELASTIC_CLIENT_CERT"`                  // mTLS 클라이언트 인증서 파일
+		ClientKey              string `yaml:"clientKey" env:"ELASTIC_CLIENT_KEY"`                    // mTLS 클라이언트 키 파일
+		InsecureSkipVerify     bool   `yaml:"insecureSkipVerify" env:"ELASTIC_INSECURE_SKIP_VERIFY"` // 인증서 검증 비활성화 (테스트 환경에서만 사용)
+
+		// 문서 저장 방식
+		// action: index(기본값), create, update (update는 documentID uidVersion 필수)
+		// documentID: 없으면 자동 생성, uid(최신 버전만 유지), uidVersion(버전별 유지)
+		Action     string `yaml:"action" env:"ELASTIC_ACTION"`
+		DocumentID string `yaml:"documentID" env:"ELASTIC_DOCUMENT_ID"`
+
+		// bulk 요청 재시도 설정
+		RetryMax        int           `yaml:"retryMax" env:"ELASTIC_RETRY_MAX"`
+		RetryBackoff    time.Duration `yaml:"retryBackoff" env:"ELASTIC_RETRY_BACKOFF"`
+		RetryMaxBackoff time.Duration `yaml:"retryMaxBackoff" env:"ELASTIC_RETRY_MAX_BACKOFF"`
+		RetryJitter     float64       `yaml:"retryJitter" env:"ELASTIC_RETRY_JITTER" default:"0.2"` // 0이면 편차 없이 재시도
 	} `yaml:"elasticsearch"`
+
+	// event buffer 설정
+	Buffer struct {
+		FlushMaxCount int           `yaml:"flushMaxCount" env:"FLUSH_MAX_COUNT" default:"100"`     // flush할 최대 이벤트 개수
+		FlushMaxBytes int           `yaml:"flushMaxBytes" env:"FLUSH_MAX_BYTES" default:"5242880"` // flush할 최대 이벤트 크기 (5MB)
+		FlushInterval time.Duration `yaml:"flushInterval" env:"FLUSH_INTERVAL" default:"5s"`       // 최대 flush 주기
+		QueueSize     int           `yaml:"queueSize" env:"QUEUE_SIZE" default:"1000"`             // 수신 대기열 크기
+		DropWhenFull  bool          `yaml:"dropWhenFull" env:"DROP_WHEN_FULL"`                     // 수신 대기열이 가득 차면 이벤트를 버림 (기본값 false, 대기)
+	} `yaml:"buffer" env:"BUFFER"`
+
+	// 로그 설정 (설정 reload 시 바로 적용)
+	Log struct {
+		Level string `yaml:"level" env:"LOG_LEVEL"` // debug, info(기본값), warn, error
+	} `yaml:"log"`
+
+	// metrics http 서버 설정
+	Server struct {
+		Address string `yaml:"address" env:"SERVER_ADDRESS" default:":8080"`
+	} `yaml:"server"`
 }
 
-// LoadConfig 설정 파일을 읽어서 Config 구조체로 반환
-func LoadConfig(fileName string) (*Config, error) {
+// LoadConfig 설정 파일, 환경변수, command-line flag를 읽어서 Config 구조체로 반환
+// 검증 에러는 모두 모아서 반환
+func LoadConfig(loader *pkgconfig.Loader) (*Config, pkgconfig.Sources, error) {
 	config := &Config{}
-	if err := readFile(fileName, config); err != nil {
-		return nil, fmt.Errorf("failed config read file: %w", err)
-	}
-	readEnv(config)
-	ShowConfig(config)
-
-	return config, checkConfig(config)
+	sources, err := loader.Load(config, func() error { return checkConfig(config) })
+	return config, sources, err
 }
 
-// checkConfig 필수 설정 값이 있는지 확인
+// checkConfig 설정 값 확인 (필수 값은 required 태그로 확인)
 func checkConfig(config *Config) error {
+	errs := make([]error, 0)
+
 	// Kafka
-	if config.Kafka.Broker == nil || len(config.Kafka.Broker) == 0 {
-		return fmt.Errorf("config kafka broker required")
-	}
-	if config.Kafka.GroupID == "" {
-		return fmt.Errorf("config kafka groupID required")
+	if err := config.Kafka.TLS.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("config kafka %w", err))
 	}
-	if config.Kafka.Topic == "" {
-		return fmt.Errorf("config kafka topic required")
+	if err := config.Kafka.SASL.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("config kafka %w", err))
 	}
 
 	// ElasticSearch
-	if config.ElasticSearch.Addresses == nil || len(config.ElasticSearch.Addresses) == 0 {
-		return fmt.Errorf("config elasticsearch addresses required")
-	}
-
-	return nil
-}
-
-// readFile 설정 파일을 읽어서 Config 구조체로 반환
-func readFile(filename string, config *Config) error {
-	file, err := os.ReadFile(filename)
-	if err != nil {
-		return err
-	}
-
-	if err := yaml.Unmarshal(file, config); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// readEnv 환경변수를 읽어서 Config 구조체에 설정
-func readEnv(config *Config) {
-	// Kafka
-	if env := os.Getenv(EnvKafkaBroker); env != "" {
-		brokers := strings.Split(env, ",")
-		config.Kafka.Broker = brokers
-	}
-	// Consumer 그룹 설정
-	if env := os.Getenv(EnvKafkaGoupID); env != "" {
-		config.Kafka.GroupID = env
-	}
-	if env := os.Getenv(EnvKafkaTopic); env != "" {
-		config.Kafka.Topic = env
-	}
-	if env := os.Getenv(EnvKafkaRebanlance); env != "" {
-		config.Kafka.RebalanceStrategy = env
-	}
-	// Dead Letter Queue 설정
-	if env := os.Getenv(EnvKafkaDlqTopic); env != "" {
-		config.Kafka.DlqTopic = env
-	}
-	if env := os.Getenv(EnvKafkaTimeout); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.Timeout = value
-		}
+	if len(config.ElasticSearch.Addresses) == 0 && config.ElasticSearch.CloudID == "" {
+		errs = append(errs, fmt.Errorf("config elasticsearch addresses or cloudID required"))
 	}
-	if env := os.Getenv(EnvKafkaRetry); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.Retry = value
-		}
+	if (config.ElasticSearch.ClientCert == "") != (config.ElasticSearch.ClientKey == "") {
+		errs = append(errs, fmt.Errorf("config elasticsearch clientCert and clientKey must be set together"))
 	}
-	if env := os.Getenv(EnvKafkaRetryBackoff); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.RetryBackoff = value
-		}
+	if config.ElasticSearch.RetryJitter < 0 || config.ElasticSearch.RetryJitter > 1 {
+		errs = append(errs, fmt.Errorf("config elasticsearch retryJitter must be between 0 and 1: %v", config.ElasticSearch.RetryJitter))
 	}
-	if env := os.Getenv(EnvKafkaFlushMsg); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.FlushMsg = value
+	if config.ElasticSearch.CertificateFingerprint != "" {
+		if _, err := es.ParseFingerprint(config.ElasticSearch.CertificateFingerprint); err != nil {
+			errs = append(errs, fmt.Errorf("config elasticsearch %w", err))
 		}
 	}
-	if env := os.Getenv(EnvKafkaFlushSec); env != "" {
-		if value, err := time.ParseDuration(env); err == nil {
-			config.Kafka.FlushTime = value
-		}
+	if err := kube.CheckDocumentID(config.ElasticSearch.DocumentID); err != nil {
+		errs = append(errs, fmt.Errorf("config elasticsearch %w", err))
 	}
-	if env := os.Getenv(EnvKafkaFlushByte); env != "" {
-		if value, err := strconv.Atoi(env); err == nil {
-			config.Kafka.FlushByte = value
-		}
+	if err := es.CheckAction(config.ElasticSearch.Action, config.ElasticSearch.DocumentID == kube.DocumentIDUIDVersion); err != nil {
+		errs = append(errs, fmt.Errorf("config elasticsearch %w", err))
 	}
 
-	// Elasticsearch
-	if env := os.Getenv(EnvElasticAddress); env != "" {
-		addresses := strings.Split(env, ",")
-		config.ElasticSearch.Addresses = addresses
-	}
-	if env := os.Getenv(EnvElasticUser); env != "" {
-		config.ElasticSearch.User = env
-	}
-	if env := os.Getenv(EnvElasticPass); env != "" {
-		config.ElasticSearch.Pass = env
+	if _, err := logger.ParseLevel(config.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("config log %w", err))
 	}
+
+	return errors.Join(errs...)
 }
@@ -1,27 +1,11 @@
 package config
 
 import (
-	"strings"
-
-	"example.com/stradvision-project/pkg/logger"
-	"go.uber.org/zap"
+	pkgconfig "example.com/stradvision-project/pkg/config"
 )
 
-func ShowConfig(config *Config) {
-	logger.Debug("kafka consumer",
-		zap.String("broker", strings.Join(config.Kafka.Broker, ",")),
-		zap.String("groupID", config.Kafka.GroupID), zap.String("topic", config.Kafka.Topic),
-		zap.String("rebalanceStrategy", config.Kafka.RebalanceStrategy),
-	)
-
-	logger.Debug("kafka producer",
-		zap.String("DlqTopic", config.Kafka.DlqTopic),
-	)
-
-	logger.Debug("elasticsearch",
-		zap.String("address", strings.Join(config.ElasticSearch.Addresses, ",")),
-		zap.String("username", config.ElasticSearch.User),
-		zap.String("password", config.ElasticSearch.Pass),
-		zap.String("index", config.ElasticSearch.Index),
-	)
+// ShowConfig 전체 설정을 출처(file, env, default)와 함께 출력
+// secret 필드(password, API key, token 등)는 가림
+func ShowConfig(config *Config, sources pkgconfig.Sources) {
+	pkgconfig.Print(config, sources)
 }
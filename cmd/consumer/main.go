@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"os"
 	"strconv"
 
 	"example.com/stradvision-project/cmd/consumer/app"
 	"example.com/stradvision-project/cmd/consumer/config"
+	pkgconfig "example.com/stradvision-project/pkg/config"
 	"example.com/stradvision-project/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -18,8 +21,6 @@ const (
 	EnvLogAge      string = "LOG_AGE"
 	EnvLogBack     string = "LOG_BACK"
 	EnvLogCompress string = "LOG_COMPRESS"
-
-	DefaultConfigPath string = "/etc/stradvision/config.yaml"
 )
 
 func init() {
@@ -39,14 +40,50 @@ func init() {
 }
 
 func main() {
-	cfg, err := config.LoadConfig(DefaultConfigPath)
+	loader, err := pkgconfig.NewLoader(AppName, &config.Config{}, os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+
+	cfg, sources, err := config.LoadConfig(loader)
+	if loader.Mode() != pkgconfig.ModeRun {
+		os.Exit(loader.Report(os.Stdout, cfg, sources, err))
+	}
 	if err != nil {
 		logger.Panic("failed to load config", zap.String("App", AppName), zap.Error(err))
 	}
+	_ = logger.SetLevel(cfg.Log.Level)
+	config.ShowConfig(cfg, sources)
 
 	app, err := app.NewApplication(cfg)
 	if err != nil {
 		logger.Panic("failed to create application", zap.String("App", AppName), zap.Error(err))
 	}
-	app.Run()
+
+	// 설정 파일이 바뀌거나 SIGHUP을 받으면 설정을 다시 읽어서 적용
+	watcher := pkgconfig.NewWatcher(loader.File(), func() { reload(loader, app) })
+	go watcher.Run()
+	defer watcher.Close()
+
+	if err := app.Run(); err != nil {
+		logger.Fatal("failed to run application", zap.String("App", AppName), zap.Error(err))
+	}
+}
+
+// reload 설정을 다시 읽고 검증하여 실행 중인 application에 적용 (검증에 실패하면 기존 설정 유지)
+func reload(loader *pkgconfig.Loader, application *app.Application) {
+	cfg, sources, err := config.LoadConfig(loader)
+	if err != nil {
+		logger.Error("failed to reload config, keep current config", zap.String("App", AppName), zap.Error(err))
+		return
+	}
+	_ = logger.SetLevel(cfg.Log.Level)
+	config.ShowConfig(cfg, sources)
+
+	if err := application.Reload(cfg); err != nil {
+		logger.Error("failed to apply reloaded config", zap.String("App", AppName), zap.Error(err))
+	}
 }
@@ -4,28 +4,47 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"example.com/stradvision-project/cmd/recovery/config"
 	"example.com/stradvision-project/pkg/kafka/consumer"
 	"example.com/stradvision-project/pkg/kube"
 	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/metrics"
 	"example.com/stradvision-project/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultShutdownTimeout 종료 시 남은 이벤트 처리를 기다리는 최대 시간
+	// kubernetes terminationGracePeriodSeconds(30초)보다 짧아야 함
+	DefaultShutdownTimeout = 25 * time.Second
 )
 
 type Application struct {
-	// kafka
-	kc *consumer.KafkaConsumer
+	// kafka (설정 reload 시 교체)
+	kc atomic.Pointer[consumer.KafkaConsumer]
 
 	// storage
 	stg *storage.Handler
 
 	// data buffer
 	buf *kube.EventBuffer
+
+	// metrics
+	server *metrics.Server
+
+	consumeErr chan error     // 실행 중인 kafka consumer가 복구할 수 없는 에러로 종료된 경우
+	config     *config.Config // 마지막으로 적용한 설정
+	reloadMu   sync.Mutex     // Reload, shutdown 동시 실행 방지
+	stopping   bool
 }
 
 func NewApplication(config *config.Config) (*Application, error) {
-	app := &Application{}
+	app := &Application{consumeErr: make(chan error, 1), config: config}
 
 	// storage handler
 	stg, err := storage.NewHandler(
@@ -39,41 +58,134 @@ func NewApplication(config *config.Config) (*Application, error) {
 	app.stg = stg
 
 	// kafka consumer
+	kc, err := app.newConsumer(config)
+	if err != nil {
+		return nil, err
+	}
+	app.kc.Store(kc)
+
+	// data buffer
+	buf, err := kube.NewEventBuffer(app.bufferDo, app.bufferErrHandler, bufferOptions(config)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event buffer: %w", err)
+	}
+	app.buf = buf
+
+	// metrics http server
+	app.server = metrics.NewServer(config.Server.Address)
+	app.registerHealth()
+
+	return app, nil
+}
+
+// newConsumer kafka consumer 생성
+func (app *Application) newConsumer(config *config.Config) (*consumer.KafkaConsumer, error) {
 	kc, err := consumer.NewKafkaConsumer(
 		config.Kafka.Broker, config.Kafka.GroupID, config.Kafka.Topic,
 		consumer.WithErrFunc(ConsumerErrorHandler),
 		consumer.WithDoFunc(app.ConsumerDo),
 		consumer.WithBalanceStrategy(config.Kafka.RebalanceStrategy),
+		consumer.WithTLS(config.Kafka.TLS),
+		consumer.WithSASL(config.Kafka.SASL),
+		consumer.WithCommitInterval(config.Kafka.CommitInterval),
+		consumer.WithAckTimeout(config.Kafka.AckTimeout),
+		consumer.WithConsumeRetryMax(config.Kafka.ConsumeRetryMax),
+		consumer.WithConsumeRetryBackoff(config.Kafka.ConsumeRetryBackoff),
+		consumer.WithConsumeRetryMaxBackoff(config.Kafka.ConsumeRetryMaxBackoff),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
 	}
-	app.kc = kc
+	return kc, nil
+}
 
-	// data buffer
-	buf, err := kube.NewEventBuffer(app.bufferDo, app.bufferErrHandler)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create event buffer: %w", err)
+// bufferOptions event buffer 설정
+func bufferOptions(config *config.Config) []kube.BufferOption {
+	return []kube.BufferOption{
+		kube.WithFlushMaxCount(config.Buffer.FlushMaxCount),
+		kube.WithFlushMaxBytes(config.Buffer.FlushMaxBytes),
+		kube.WithFlushInterval(config.Buffer.FlushInterval),
+		kube.WithQueueSize(config.Buffer.QueueSize),
+		kube.WithDropWhenFull(config.Buffer.DropWhenFull),
 	}
-	app.buf = buf
-
-	return app, nil
 }
 
-func (app *Application) Run() {
+// Run application 실행
+// kafka consumer가 복구할 수 없는 에러로 종료되면 정리 후 에러를 반환
+func (app *Application) Run() error {
 	logger.Info("start recovery application ...	")
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go app.buf.Run()
-	go app.kc.Run()
+	go app.runConsumer(app.kc.Load())
+	go app.runServer()
+
+	var runErr error
+	select {
+	case <-sigChan:
+	case runErr = <-app.consumeErr:
+		logger.Error("kafka consumer stopped", zap.Error(runErr))
+	}
 
-	<-sigChan
-	app.buf.Close()
-	logger.Info("close buffer ...")
-	app.kc.Close()
-	logger.Info("close consumer ...")
+	app.shutdown()
 
 	logger.Info("stop recovery application ...")
+	return runErr
+}
+
+// runConsumer kafka consumer 실행
+// reload로 교체되어 종료된 consumer의 결과는 무시
+func (app *Application) runConsumer(kc *consumer.KafkaConsumer) {
+	err := kc.Run()
+	if err == nil || app.kc.Load() != kc {
+		return
+	}
+	select {
+	case app.consumeErr <- err:
+	default:
+	}
+}
+
+// shutdown 수신 중지, buffer flush, storage 저장, offset commit 순서로 종료
+// DefaultShutdownTimeout 안에 끝나지 않으면 기다리지 않고 종료 (commit되지 않은 메시지는 다시 수신됨)
+func (app *Application) shutdown() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// 진행 중인 reload가 끝난 후 종료
+		app.reloadMu.Lock()
+		app.stopping = true
+		app.reloadMu.Unlock()
+
+		kc := app.kc.Load()
+		kc.Pause()
+		logger.Info("pause consumer ...")
+		app.buf.Close()
+		logger.Info("close buffer ...")
+		if err := app.stg.Close(); err != nil {
+			logger.Error("failed to close storage", zap.Error(err))
+		}
+		logger.Info("close storage ...")
+		kc.Close()
+		logger.Info("close consumer ...")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(DefaultShutdownTimeout):
+		logger.Error("shutdown timed out", zap.Duration("timeout", DefaultShutdownTimeout))
+	}
+
+	app.server.Close()
+	logger.Info("close metrics server ...")
+}
+
+// runServer metrics http 서버 실행
+func (app *Application) runServer() {
+	if err := app.server.Run(); err != nil {
+		logger.Error("failed to run metrics server", zap.Error(err))
+	}
 }
@@ -8,8 +8,9 @@ import (
 	"go.uber.org/zap"
 )
 
-func (app *Application) bufferDo(events []*kube.Event) (err error) {
+func (app *Application) bufferDo(events []kube.Document) (err error) {
 	// storage flush
+	written := make([]kube.Document, 0, len(events))
 	for _, event := range events {
 		var data []byte
 		data, err = json.Marshal(event)
@@ -22,24 +23,31 @@ func (app *Application) bufferDo(events []*kube.Event) (err error) {
 		if err = app.stg.WriteData(data); err != nil {
 			continue
 		}
+		written = append(written, event)
 
 		logger.Debug("storage flush",
-			zap.String("event", event.Metadata.Name),
-			zap.String("kind", event.Regarding.Kind),
-			zap.String("namespace", event.Regarding.Namespace),
-			zap.String("name", event.Regarding.Name),
+			zap.String("event", event.Meta().Name),
+			zap.String("namespace", event.Meta().Namespace),
 		)
 	}
 
+	// 디스크에 반영된 이벤트만 ack 처리하여 offset commit
+	if syncErr := app.stg.Sync(); syncErr != nil {
+		return syncErr
+	}
+	for _, event := range written {
+		event.Ack()
+	}
+
 	return err
 }
 
-func (app *Application) bufferErrHandler(err error, events []*kube.Event) {
+func (app *Application) bufferErrHandler(err error, events []kube.Document) {
 	// log error
 	logger.Error("failed to flush events", zap.Error(err))
 
 	// log write
 	for _, event := range events {
-		logger.Error("failed storage flush", zap.String("event", event.Metadata.Name))
+		logger.Error("failed storage flush", zap.String("event", event.Meta().Name))
 	}
 }
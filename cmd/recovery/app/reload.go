@@ -0,0 +1,64 @@
+package app
+
+import (
+	"reflect"
+
+	"example.com/stradvision-project/cmd/recovery/config"
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Reload 변경된 설정을 실행 중에 적용
+// buffer 임계값은 바로 적용하고, kafka 연결 설정(주소, 인증, TLS 등)이 바뀌면 consumer만 새로 만들어 교체
+// 새 consumer를 만들지 못하면 아무것도 적용하지 않고 기존 설정 유지
+// storage, 수신 대기열 크기, metrics 서버 주소는 재시작해야 적용됨
+func (app *Application) Reload(next *config.Config) error {
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+	if app.stopping {
+		return nil
+	}
+	prev := app.config
+
+	kc := app.kc.Load()
+	if !reflect.DeepEqual(prev.Kafka, next.Kafka) {
+		var err error
+		if kc, err = app.newConsumer(next); err != nil {
+			return err
+		}
+	}
+
+	if bufferChanged(prev, next) {
+		app.buf.Update(bufferOptions(next)...)
+		logger.Info("reloaded event buffer")
+	}
+	if old := app.kc.Load(); kc != old {
+		// 수신을 멈추고 이미 수신한 이벤트를 저장하여 offset commit 후 교체
+		old.Pause()
+		app.buf.Flush()
+		old.Close()
+		app.kc.Store(kc)
+		go app.runConsumer(kc)
+		logger.Info("reloaded kafka consumer", zap.Strings("broker", next.Kafka.Broker), zap.String("topic", next.Kafka.Topic))
+	}
+
+	if prev.Storage != next.Storage {
+		logger.Warn("config storage requires restart")
+	}
+	if prev.Buffer.QueueSize != next.Buffer.QueueSize {
+		logger.Warn("config buffer.queueSize requires restart")
+	}
+	if prev.Server != next.Server {
+		logger.Warn("config server requires restart")
+	}
+
+	app.config = next
+	return nil
+}
+
+// bufferChanged event buffer 임계값 변경 여부 (수신 대기열 크기 제외)
+func bufferChanged(prev, next *config.Config) bool {
+	a, b := prev.Buffer, next.Buffer
+	a.QueueSize, b.QueueSize = 0, 0
+	return a != b
+}
@@ -0,0 +1,256 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example.com/stradvision-project/cmd/recovery/config"
+	"example.com/stradvision-project/pkg/es"
+	"example.com/stradvision-project/pkg/kube"
+	"example.com/stradvision-project/pkg/logger"
+	"example.com/stradvision-project/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultReplayBatchSize = 500
+	DefaultReplayMinAge    = time.Minute
+)
+
+// Replayer storage에 저장된 이벤트를 elasticsearch로 다시 전송
+// 파일별로 처리가 끝난 위치를 checkpoint로 기록하여 중간에 종료되어도 이어서 처리
+type Replayer struct {
+	stg *storage.Handler
+	ec  *es.Client
+
+	index       string
+	action      string
+	documentID  string
+	batchSize   int
+	minAge      time.Duration
+	archivePath string
+
+	ctx    context.Context // 종료 signal을 받으면 취소 (elasticsearch 재시도 대기 중단)
+	cancel context.CancelFunc
+}
+
+func NewReplayer(config *config.Config) (*Replayer, error) {
+	r := &Replayer{
+		index:       config.ElasticSearch.Index,
+		action:      config.ElasticSearch.Action,
+		documentID:  config.ElasticSearch.DocumentID,
+		batchSize:   DefaultReplayBatchSize,
+		minAge:      DefaultReplayMinAge,
+		archivePath: config.Replay.ArchivePath,
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	if config.Replay.BatchSize > 0 {
+		r.batchSize = config.Replay.BatchSize
+	}
+	if config.Replay.MinAge > 0 {
+		r.minAge = config.Replay.MinAge
+	}
+
+	// storage handler
+	stg, err := storage.NewHandler(config.Storage.Name, config.Storage.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage handler: %w", err)
+	}
+	r.stg = stg
+
+	// elasticsearch client
+	ec, err := es.NewElasticsearchClient(
+		config.ElasticSearch.Addresses, config.ElasticSearch.User, config.ElasticSearch.Pass,
+		es.WithRetryMax(config.ElasticSearch.RetryMax),
+		es.WithRetryBackoff(config.ElasticSearch.RetryBackoff),
+		es.WithRetryMaxBackoff(config.ElasticSearch.RetryMaxBackoff),
+		es.WithRetryJitter(config.ElasticSearch.RetryJitter),
+		es.WithCloudID(config.ElasticSearch.CloudID),
+		es.WithPasswordFile(config.ElasticSearch.PassFile),
+		es.WithAPIKey(config.ElasticSearch.APIKey),
+		es.WithAPIKeyFile(config.ElasticSearch.APIKeyFile),
+		es.WithServiceToken(config.ElasticSearch.ServiceToken),
+		es.WithServiceTokenFile(config.ElasticSearch.ServiceTokenFile),
+		es.WithCACertFile(config.ElasticSearch.CACert),
+		es.WithCertificateFingerprint(config.ElasticSearch.CertificateFingerprint),
+		es.WithClientCertFile(config.ElasticSearch.ClientCert, config.ElasticSearch.ClientKey),
+		es.WithInsecureSkipVerify(config.ElasticSearch.InsecureSkipVerify),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create elasticsearch client: %w", err)
+	}
+	r.ec = ec
+
+	return r, nil
+}
+
+// Run 저장된 파일을 오래된 순서대로 처리
+func (r *Replayer) Run() error {
+	logger.Info("start recovery replay ...")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		r.cancel()
+	}()
+
+	files, err := r.stg.GetSortFileList()
+	if err != nil {
+		return fmt.Errorf("failed to get file list: %w", err)
+	}
+	// 가장 최근 파일은 recovery가 열어서 기록 중일 수 있으므로 다음 파일이 생성된 후 처리
+	if len(files) > 0 {
+		logger.Info("skip replay current file", zap.String("file", files[len(files)-1]))
+		files = files[:len(files)-1]
+	}
+
+	for _, file := range files {
+		if r.stopped() {
+			break
+		}
+
+		// 최근에 기록된 파일은 다음 실행에서 처리
+		info, err := r.stg.StatFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		if time.Since(info.ModTime()) < r.minAge {
+			logger.Info("skip replay file in use", zap.String("file", file))
+			continue
+		}
+
+		done, err := r.replayFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", file, err)
+		}
+		if !done {
+			break
+		}
+
+		if r.archivePath != "" {
+			err = r.stg.ArchiveFile(file, r.archivePath)
+		} else {
+			err = r.stg.RemoveFile(file)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to finish %s: %w", file, err)
+		}
+		logger.Info("replay file done", zap.String("file", file))
+	}
+
+	logger.Info("stop recovery replay ...")
+	return nil
+}
+
+// replayFile checkpoint 위치부터 파일을 읽어서 batchSize 단위로 전송
+// 종료 요청으로 파일을 끝까지 처리하지 못하면 false를 반환
+func (r *Replayer) replayFile(file string) (bool, error) {
+	offset, err := r.stg.ReadCheckpoint(file)
+	if err != nil {
+		return false, err
+	}
+
+	f, err := r.stg.OpenFile(file)
+	if err != nil {
+		return false, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return false, fmt.Errorf("failed to seek file: %w", err)
+	}
+	logger.Info("replay file", zap.String("file", file), zap.Int64("offset", offset))
+
+	reader := bufio.NewReader(f)
+	items := make([][]byte, 0, r.batchSize)
+	var size int64
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return false, fmt.Errorf("failed to read file: %w", readErr)
+		}
+
+		if len(line) > 0 {
+			size += int64(len(line))
+			if item, err := r.convertLine(line); err != nil {
+				logger.Error("skip invalid replay event", zap.String("file", file), zap.Int64("offset", offset+size), zap.Error(err))
+			} else {
+				items = append(items, item)
+			}
+		}
+
+		eof := errors.Is(readErr, io.EOF)
+		if len(items) >= r.batchSize || (eof && size > 0) {
+			if err := r.flush(items); err != nil {
+				return false, err
+			}
+
+			// 전송이 끝난 위치까지 checkpoint 기록
+			offset += size
+			if err := r.stg.WriteCheckpoint(file, offset); err != nil {
+				return false, err
+			}
+			items = items[:0]
+			size = 0
+		}
+
+		if eof {
+			return true, nil
+		}
+		if r.stopped() {
+			return false, nil
+		}
+	}
+}
+
+// convertLine NDJSON 한 줄을 bulk 요청 문서로 변환
+func (r *Replayer) convertLine(line []byte) ([]byte, error) {
+	event, err := kube.Decode(line)
+	if err != nil {
+		return nil, err
+	}
+
+	return es.ConvertAction(es.BulkAction{
+		Action:  r.action,
+		Index:   kube.IndexName(r.index, event),
+		ID:      event.DocumentID(r.documentID),
+		Version: event.Version(),
+	}, event)
+}
+
+// flush bulk 요청 전송
+// 재시도 후에도 실패한 문서가 있으면 에러를 반환하여 checkpoint가 진행되지 않도록 하고,
+// mapping 충돌 등 다시 보내도 실패하는 문서는 로그만 남기고 건너뜀
+func (r *Replayer) flush(items [][]byte) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	result := r.ec.WriteBulkWithRetry(r.ctx, r.index, items)
+	for _, item := range result.Failed {
+		if item.ErrorType == es.ErrorTypeRequestFailed || es.IsRetryableItem(item) {
+			return fmt.Errorf("failed to replay events: %s", item.ErrorReason)
+		}
+
+		logger.Error("skip rejected replay event",
+			zap.Int("status", item.Status),
+			zap.String("type", item.ErrorType),
+			zap.String("reason", item.ErrorReason),
+		)
+	}
+	logger.Debug("replay flush", zap.Int("count", len(items)), zap.Int("failed", len(result.Failed)))
+
+	return nil
+}
+
+func (r *Replayer) stopped() bool {
+	return r.ctx.Err() != nil
+}
@@ -1,21 +1,11 @@
 package config
 
 import (
-	"strings"
-
-	"example.com/stradvision-project/pkg/logger"
-	"go.uber.org/zap"
+	pkgconfig "example.com/stradvision-project/pkg/config"
 )
 
-func ShowConfig(config *Config) {
-	logger.Debug("kafka",
-		zap.String("broker", strings.Join(config.Kafka.Broker, ",")),
-		zap.String("groupID", config.Kafka.GroupID), zap.String("topic", config.Kafka.Topic),
-		zap.String("rebalanceStrategy", config.Kafka.RebalanceStrategy),
-	)
-
-	logger.Debug("storage",
-		zap.String("name", config.Storage.Name), zap.String("path", config.Storage.Path),
-		zap.Int("maxFileSize", config.Storage.MaxFileSize), zap.Int("maxFileCount", config.Storage.MaxFileCount),
-	)
+// ShowConfig 전체 설정을 출처(file, env, default)와 함께 출력
+// secret 필드(password, API key, token 등)는 가림
+func ShowConfig(config *Config, sources pkgconfig.Sources) {
+	pkgconfig.Print(config, sources)
 }
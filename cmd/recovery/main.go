@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"os"
 	"strconv"
 
 	"example.com/stradvision-project/cmd/recovery/app"
 	"example.com/stradvision-project/cmd/recovery/config"
+	pkgconfig "example.com/stradvision-project/pkg/config"
 	"example.com/stradvision-project/pkg/logger"
 	"go.uber.org/zap"
 )
@@ -19,7 +22,9 @@ const (
 	EnvLogBack     string = "LOG_BACK"
 	EnvLogCompress string = "LOG_COMPRESS"
 
-	DefaultConfigPath string = "/etc/stradvision/config.yaml"
+	// ModeReplay storage에 저장된 이벤트를 elasticsearch로 다시 전송하는 실행 모드
+	// ex) ./recovery replay
+	ModeReplay string = "replay"
 )
 
 func init() {
@@ -39,14 +44,82 @@ func init() {
 }
 
 func main() {
-	cfg, err := config.LoadConfig(DefaultConfigPath)
+	if len(os.Args) > 1 && os.Args[1] == ModeReplay {
+		replay(os.Args[2:])
+		return
+	}
+
+	loader := newLoader(os.Args[1:])
+	cfg, sources, err := config.LoadConfig(loader)
+	if loader.Mode() != pkgconfig.ModeRun {
+		os.Exit(loader.Report(os.Stdout, cfg, sources, err))
+	}
 	if err != nil {
 		logger.Panic("failed to load config", zap.String("App", AppName), zap.Error(err))
 	}
+	_ = logger.SetLevel(cfg.Log.Level)
+	config.ShowConfig(cfg, sources)
 
 	app, err := app.NewApplication(cfg)
 	if err != nil {
 		logger.Panic("failed to create application", zap.String("App", AppName), zap.Error(err))
 	}
-	app.Run()
+
+	// 설정 파일이 바뀌거나 SIGHUP을 받으면 설정을 다시 읽어서 적용 (replay 모드 제외)
+	watcher := pkgconfig.NewWatcher(loader.File(), func() { reload(loader, app) })
+	go watcher.Run()
+	defer watcher.Close()
+
+	if err := app.Run(); err != nil {
+		logger.Fatal("failed to run application", zap.String("App", AppName), zap.Error(err))
+	}
+}
+
+// replay storage에 저장된 이벤트를 elasticsearch로 다시 전송하고 종료
+func replay(args []string) {
+	loader := newLoader(args)
+	cfg, sources, err := config.LoadReplayConfig(loader)
+	if loader.Mode() != pkgconfig.ModeRun {
+		os.Exit(loader.Report(os.Stdout, cfg, sources, err))
+	}
+	if err != nil {
+		logger.Panic("failed to load config", zap.String("App", AppName), zap.Error(err))
+	}
+	_ = logger.SetLevel(cfg.Log.Level)
+	config.ShowConfig(cfg, sources)
+
+	replayer, err := app.NewReplayer(cfg)
+	if err != nil {
+		logger.Panic("failed to create replayer", zap.String("App", AppName), zap.Error(err))
+	}
+	if err := replayer.Run(); err != nil {
+		logger.Fatal("failed to replay", zap.String("App", AppName), zap.Error(err))
+	}
+}
+
+// reload 설정을 다시 읽고 검증하여 실행 중인 application에 적용 (검증에 실패하면 기존 설정 유지)
+func reload(loader *pkgconfig.Loader, application *app.Application) {
+	cfg, sources, err := config.LoadConfig(loader)
+	if err != nil {
+		logger.Error("failed to reload config, keep current config", zap.String("App", AppName), zap.Error(err))
+		return
+	}
+	_ = logger.SetLevel(cfg.Log.Level)
+	config.ShowConfig(cfg, sources)
+
+	if err := application.Reload(cfg); err != nil {
+		logger.Error("failed to apply reloaded config", zap.String("App", AppName), zap.Error(err))
+	}
+}
+
+// newLoader command-line 인자로 설정 loader 생성 (잘못된 인자면 사용법을 출력하고 종료)
+func newLoader(args []string) *pkgconfig.Loader {
+	loader, err := pkgconfig.NewLoader(AppName, &config.Config{}, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+	return loader
 }
@@ -1,47 +1,116 @@
-# index template 생성
-curl -u "elastic:elastic" -k -X PUT "https://localhost:30090/_template/event_template"  -H "Content-Type: application/json" -d '{
+# 이벤트 index template 생성 (rollover index: event-000001, ...)
+curl -u "elastic:elastic" -k -X PUT "https://localhost:30090/_index_template/event_template"  -H "Content-Type: application/json" -d '{
     "index_patterns": ["event-*"],
-    "settings": {
-        "index": {
-            "number_of_shards": 3,
-            "number_of_replicas": 1,
-            "refresh_interval": "5s",
-            "lifecycle": {
-                "name": "event_policy",
-                "rollover_alias": "event"
-            }
-        }
-    },
-    "mappings": {
-        "dynamic": "false",
-        "properties": {
-            "metadata": {
-                "properties": {
-                    "name": { "type": "keyword" },
-                    "namespace": { "type": "keyword" },
-                    "uid": { "type": "keyword" },
-                    "resourceVersion": { "type": "keyword" },
-                    "creationTimestamp": { "type": "date" }
+    "priority": 100,
+    "template": {
+        "settings": {
+            "index": {
+                "number_of_shards": 3,
+                "number_of_replicas": 1,
+                "refresh_interval": "5s",
+                "lifecycle": {
+                    "name": "event_policy",
+                    "rollover_alias": "event"
                 }
-            },
-            "eventTime": { "type": "date" },
-            "reportingController": { "type": "keyword" },
-            "reason": { "type": "keyword" },
-            "regarding": {
-                "properties": {
-                    "kind": { "type": "keyword" },
-                    "namespace": { "type": "keyword" },
-                    "name": { "type": "keyword" },
-                    "uid": { "type": "keyword" },
-                    "apiVersion": { "type": "keyword" },
-                    "resourceVersion": { "type": "keyword" }
+            }
+        },
+        "mappings": {
+            "dynamic": "false",
+            "properties": {
+                "cluster": { "type": "keyword" },
+                "metadata": {
+                    "properties": {
+                        "name": { "type": "keyword" },
+                        "namespace": { "type": "keyword" },
+                        "uid": { "type": "keyword" },
+                        "resourceVersion": { "type": "keyword" },
+                        "creationTimestamp": { "type": "date" }
+                    }
+                },
+                "eventTime": { "type": "date" },
+                "reportingController": { "type": "keyword" },
+                "reason": { "type": "keyword" },
+                "regarding": {
+                    "properties": {
+                        "kind": { "type": "keyword" },
+                        "namespace": { "type": "keyword" },
+                        "name": { "type": "keyword" },
+                        "uid": { "type": "keyword" },
+                        "apiVersion": { "type": "keyword" },
+                        "resourceVersion": { "type": "keyword" }
+                    }
+                },
+                "note": { "type": "text" },
+                "type": { "type": "keyword" },
+                "deprecatedFirstTimestamp": { "type": "date" },
+                "deprecatedLastTimestamp": { "type": "date" },
+                "deprecatedCount": { "type": "integer" },
+                "series": {
+                    "properties": {
+                        "count": { "type": "integer" },
+                        "lastObservedTime": { "type": "date" }
+                    }
+                },
+                "operation": { "type": "keyword" },
+                "involved": {
+                    "properties": {
+                        "owners": {
+                            "properties": {
+                                "kind": { "type": "keyword" },
+                                "name": { "type": "keyword" },
+                                "uid": { "type": "keyword" }
+                            }
+                        },
+                        "nodeName": { "type": "keyword" },
+                        "labels": { "type": "flattened" },
+                        "annotations": { "type": "flattened" },
+                        "images": { "type": "keyword" }
+                    }
+                },
+                "aggregation": {
+                    "properties": {
+                        "count": { "type": "integer" },
+                        "firstTimestamp": { "type": "date" },
+                        "lastTimestamp": { "type": "date" }
+                    }
                 }
-            },
-            "note": { "type": "text" },
-            "type": { "type": "keyword" },
-            "deprecatedFirstTimestamp": { "type": "date" },
-            "deprecatedLastTimestamp": { "type": "date" },
-            "deprecatedCount": { "type": "integer" }
+            }
+        }
+    }
+}'
+
+# 리소스 index template 생성 (event-object-{kind}, ex. event-object-pod)
+# 이벤트 index template보다 priority가 높아 event-* 대신 적용되며, 문서 ID(uid)로 갱신하므로 rollover하지 않음
+curl -u "elastic:elastic" -k -X PUT "https://localhost:30090/_index_template/event_object_template"  -H "Content-Type: application/json" -d '{
+    "index_patterns": ["event-object-*"],
+    "priority": 200,
+    "template": {
+        "settings": {
+            "index": {
+                "number_of_shards": 1,
+                "number_of_replicas": 1,
+                "refresh_interval": "5s"
+            }
+        },
+        "mappings": {
+            "dynamic": "false",
+            "properties": {
+                "cluster": { "type": "keyword" },
+                "kind": { "type": "keyword" },
+                "apiVersion": { "type": "keyword" },
+                "metadata": {
+                    "properties": {
+                        "name": { "type": "keyword" },
+                        "namespace": { "type": "keyword" },
+                        "uid": { "type": "keyword" },
+                        "resourceVersion": { "type": "keyword" },
+                        "creationTimestamp": { "type": "date" }
+                    }
+                },
+                "timestamp": { "type": "date" },
+                "operation": { "type": "keyword" },
+                "object": { "type": "flattened" }
+            }
         }
     }
 }'
@@ -0,0 +1,26 @@
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Exponential attempt번째 연속 실패 후 대기할 시간
+// base부터 2배씩 증가하며 max를 넘지 않음
+// jitter: 대기 시간의 무작위 편차 비율 (0이면 편차 없음, 0.2면 ±20%)
+func Exponential(attempt int, base, max time.Duration, jitter float64) time.Duration {
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	if jitter > 0 {
+		delta := float64(d) * jitter
+		d = time.Duration(float64(d) - delta + rand.Float64()*2*delta)
+	}
+
+	return d
+}
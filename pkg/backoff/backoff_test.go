@@ -0,0 +1,29 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponential(t *testing.T) {
+	expected := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, want := range expected {
+		if got := Exponential(i+1, 100*time.Millisecond, time.Second, 0); got != want {
+			t.Errorf("Exponential(%d) = %v, want %v", i+1, got, want)
+		}
+	}
+
+	// jitter 비율 안에서 편차
+	for i := 0; i < 100; i++ {
+		if got := Exponential(1, time.Second, time.Second, 0.2); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("Exponential() with jitter = %v, want 0.8s ~ 1.2s", got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"example.com/stradvision-project/pkg/logger"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted secret 필드 출력 값
+const Redacted = "[REDACTED]"
+
+// Source 설정 값 출처
+type Source string
+
+const (
+	SourceDefault Source = "default" // default 태그 값 또는 설정하지 않음 (각 컴포넌트의 기본값 사용)
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Sources 설정 경로(yaml key, ex. kafka.sasl.password)별 출처
+// 기록되지 않은 경로는 상위 경로의 출처를 따르고, 없으면 SourceDefault
+type Sources map[string]Source
+
+// Get 설정 경로의 출처
+func (s Sources) Get(path string) Source {
+	for {
+		if source, ok := s[path]; ok {
+			return source
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			return SourceDefault
+		}
+		path = path[:i]
+	}
+}
+
+// FileSources yaml 파일에 있는 설정 경로를 SourceFile로 기록
+func FileSources(data []byte) (Sources, error) {
+	sources := make(Sources)
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) > 0 {
+		sources.addNode("", node.Content[0])
+	}
+	return sources, nil
+}
+
+func (s Sources) addNode(path string, node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			s.addNode(joinPath(path, node.Content[i].Value), node.Content[i+1])
+		}
+	case yaml.SequenceNode:
+		s[path] = SourceFile
+		for i, item := range node.Content {
+			s.addNode(fmt.Sprintf("%s[%d]", path, i), item)
+		}
+	default:
+		if path != "" {
+			s[path] = SourceFile
+		}
+	}
+}
+
+// Field 설정 경로별 값
+type Field struct {
+	Path   string
+	Value  string
+	Source Source
+	Secret bool // `secret:"true"` 태그가 있는 필드
+}
+
+// Fields 설정 구조체를 yaml 경로별 값으로 펼쳐 출처와 함께 반환
+// secret 필드는 값이 있으면 Redacted로 가림
+func Fields(config interface{}, sources Sources) []Field {
+	fields := flatten(config)
+	for i := range fields {
+		fields[i].Source = sources.Get(fields[i].Path)
+		if fields[i].Secret && fields[i].Value != "" {
+			fields[i].Value = Redacted
+		}
+	}
+	return fields
+}
+
+// Print 전체 설정을 출처와 함께 로그로 출력 (secret 필드는 가림)
+func Print(config interface{}, sources Sources) {
+	fields := Fields(config, sources)
+	zapFields := make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		zapFields = append(zapFields, zap.String(field.Path, field.Value+" ("+string(field.Source)+")"))
+	}
+	logger.Info("effective config", zapFields...)
+}
+
+// flatten 설정 구조체를 yaml 경로별 값으로 펼침 (secret 필드도 원래 값)
+func flatten(config interface{}) []Field {
+	fields := make([]Field, 0)
+	flattenValue(&fields, "", reflect.ValueOf(config), false)
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func flattenValue(fields *[]Field, path string, v reflect.Value, secret bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			*fields = append(*fields, Field{Path: path, Secret: secret})
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == durationType:
+		*fields = append(*fields, Field{Path: path, Value: time.Duration(v.Int()).String(), Secret: secret})
+	case v.Kind() == reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, ok := yamlName(f)
+			if !ok {
+				continue
+			}
+			flattenValue(fields, joinPath(path, name), v.Field(i), secret || f.Tag.Get("secret") == "true")
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct:
+		for i := 0; i < v.Len(); i++ {
+			flattenValue(fields, fmt.Sprintf("%s[%d]", path, i), v.Index(i), secret)
+		}
+	case v.Kind() == reflect.Slice:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, fmt.Sprint(v.Index(i).Interface()))
+		}
+		*fields = append(*fields, Field{Path: path, Value: strings.Join(values, ","), Secret: secret})
+	case v.Kind() == reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprintf("%v=%v", key.Interface(), v.MapIndex(key).Interface()))
+		}
+		sort.Strings(keys)
+		*fields = append(*fields, Field{Path: path, Value: strings.Join(keys, ","), Secret: secret})
+	default:
+		*fields = append(*fields, Field{Path: path, Value: fmt.Sprint(v.Interface()), Secret: secret})
+	}
+}
+
+// yamlName yaml 태그의 이름 (없으면 소문자 필드 이름, "-"이거나 비공개 필드면 false)
+func yamlName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = strings.ToLower(f.Name)
+	}
+	return name, true
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Kafka struct {
+		Broker  []string      `yaml:"broker"`
+		Timeout time.Duration `yaml:"timeout"`
+		SASL    struct {
+			Username string `yaml:"username"`
+			Password string `yaml:"password" secret:"true"`
+		} `yaml:"sasl"`
+	} `yaml:"kafka"`
+	Clusters []struct {
+		Name string `yaml:"name"`
+	} `yaml:"clusters"`
+	Token  string `yaml:"token" secret:"true"`
+	hidden string
+}
+
+func TestFields(t *testing.T) {
+	data := []byte(`
+kafka:
+  broker:
+    - kafka:9092
+  sasl:
+    username: client
+    password: secret
+clusters:
+  - name: local
+`)
+	sources, err := FileSources(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &testConfig{}
+	config.Kafka.Broker = []string{"kafka:9092"}
+	config.Kafka.SASL.Username = "client"
+	config.Kafka.SASL.Password = "secret"
+	config.Clusters = append(config.Clusters, struct {
+		Name string `yaml:"name"`
+	}{Name: "local"})
+	config.Kafka.Timeout = 3 * time.Second
+	sources["kafka.timeout"] = SourceEnv
+
+	want := map[string]Field{
+		"kafka.broker":        {Value: "kafka:9092", Source: SourceFile},
+		"kafka.timeout":       {Value: "3s", Source: SourceEnv},
+		"kafka.sasl.username": {Value: "client", Source: SourceFile},
+		"kafka.sasl.password": {Value: Redacted, Source: SourceFile, Secret: true},
+		"clusters[0].name":    {Value: "local", Source: SourceFile},
+		"token":               {Value: "", Source: SourceDefault, Secret: true},
+	}
+
+	fields := Fields(config, sources)
+	if len(fields) != len(want) {
+		t.Fatalf("Fields() = %+v, want %d fields", fields, len(want))
+	}
+	for _, field := range fields {
+		w, ok := want[field.Path]
+		if !ok {
+			t.Errorf("unexpected field %s", field.Path)
+			continue
+		}
+		if field.Value != w.Value || field.Source != w.Source || field.Secret != w.Secret {
+			t.Errorf("%s = %+v, want %+v", field.Path, field, w)
+		}
+	}
+}
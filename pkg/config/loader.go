@@ -0,0 +1,335 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mode command-line으로 지정한 실행 모드
+type Mode int
+
+const (
+	ModeRun      Mode = iota // 설정을 읽고 프로그램 실행
+	ModePrint                // --print-config: 적용된 설정을 출처와 함께 출력하고 종료
+	ModeValidate             // --validate-config: 설정을 검증하고 종료
+)
+
+// Loader 설정 구조체를 기본값 → 설정 파일 → 환경변수 → command-line flag 순서로 채움
+//
+// 설정 구조체의 필드 태그
+//   - yaml: 설정 파일의 key, 설정 경로(ex. kafka.broker)와 flag 이름(--kafka.broker)으로도 사용
+//   - env: 환경변수 이름, 구조체 필드에 설정하면 하위 필드 환경변수의 prefix (ex. KAFKA_TLS + ENABLED)
+//   - default: 기본값
+//   - required: "true"면 값이 없을 때 검증 에러
+//   - secret: "true"면 출력 시 값을 가림
+//
+// 목록([]string)은 환경변수와 flag에서 ','로 구분하고, 구조체 목록은 설정 파일에서만 설정
+type Loader struct {
+	name  string
+	file  string
+	mode  Mode
+	flags map[string]string // command-line으로 설정한 값 (설정 경로 → 값)
+}
+
+// NewLoader command-line 인자를 파싱하여 Loader 생성
+// 공통 flag(--config, --print-config, --validate-config)와 config의 설정 경로별 flag를 받음
+// -h, --help는 사용법을 출력하고 flag.ErrHelp 반환
+func NewLoader(name string, config interface{}, args []string, options ...Option) (*Loader, error) {
+	c := fromOptions(options...)
+
+	l := &Loader{name: name, flags: make(map[string]string)}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.output)
+	fs.StringVar(&l.file, "config", c.file, "config file path")
+	printConfig := fs.Bool("print-config", false, "print effective config (secrets redacted) and exit")
+	validateConfig := fs.Bool("validate-config", false, "validate config and exit")
+
+	values := make(map[string]*flagValue)
+	for _, f := range fieldsOf(config) {
+		value := &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		values[f.path] = value
+		usage := "config " + f.path
+		if f.env != "" {
+			usage += " (env " + f.env + ")"
+		}
+		fs.Var(value, f.path, usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if value, ok := values[f.Name]; ok {
+			l.flags[f.Name] = value.value
+		}
+	})
+
+	switch {
+	case *printConfig:
+		l.mode = ModePrint
+	case *validateConfig:
+		l.mode = ModeValidate
+	}
+
+	return l, nil
+}
+
+// File 설정 파일 경로
+func (l *Loader) File() string {
+	return l.file
+}
+
+// Mode 실행 모드
+func (l *Loader) Mode() Mode {
+	return l.mode
+}
+
+// Load config를 기본값 → 설정 파일 → 환경변수 → command-line flag 순서로 채우고 검증
+// required 태그 검증과 validate(없으면 nil)의 에러를 모두 모아서 반환
+func (l *Loader) Load(config interface{}, validate func() error) (Sources, error) {
+	sources := make(Sources)
+	errs := make([]error, 0)
+
+	fields := fieldsOf(config)
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := setValue(f.value, f.def); err != nil {
+			errs = append(errs, fmt.Errorf("config %s default %q invalid: %w", f.path, f.def, err))
+		}
+	}
+
+	if l.file != "" {
+		data, err := os.ReadFile(l.file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+		if sources, err = FileSources(data); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+		// 설정 파일에서 목록을 바꾸면 필드의 위치가 바뀌므로 다시 조회
+		fields = fieldsOf(config)
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		env, ok := os.LookupEnv(f.env)
+		if !ok || env == "" {
+			continue
+		}
+		if err := setValue(f.value, env); err != nil {
+			errs = append(errs, fmt.Errorf("env %s invalid: %w", f.env, err))
+			continue
+		}
+		sources[f.path] = SourceEnv
+	}
+
+	for _, f := range fields {
+		value, ok := l.flags[f.path]
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("flag --%s invalid: %w", f.path, err))
+			continue
+		}
+		sources[f.path] = SourceFlag
+	}
+
+	for _, f := range fields {
+		if f.required && isEmpty(f.value) {
+			errs = append(errs, fmt.Errorf("config %s required", f.path))
+		}
+	}
+	if validate != nil {
+		if err := validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return sources, errors.Join(errs...)
+}
+
+// Report --print-config, --validate-config 결과를 w에 출력하고 exit code 반환
+// err: Load의 에러
+func (l *Loader) Report(w io.Writer, config interface{}, sources Sources, err error) int {
+	if l.mode == ModePrint && config != nil {
+		for _, field := range Fields(config, sources) {
+			fmt.Fprintf(w, "%s = %s (%s)\n", field.Path, field.Value, field.Source)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(w, "%s: invalid config %s\n", l.name, l.file)
+		for _, e := range unwrapJoined(err) {
+			fmt.Fprintf(w, "  - %s\n", e)
+		}
+		return 1
+	}
+	if l.mode == ModeValidate {
+		fmt.Fprintf(w, "%s: config %s is valid\n", l.name, l.file)
+	}
+	return 0
+}
+
+// unwrapJoined errors.Join으로 묶인 에러 목록
+func unwrapJoined(err error) []error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := make([]error, 0)
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, unwrapJoined(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+// field 환경변수, flag로 설정할 수 있는 설정 필드
+type field struct {
+	path     string
+	env      string
+	def      string
+	required bool
+	value    reflect.Value
+}
+
+// fieldsOf 설정 구조체의 설정 필드 목록 (구조체 목록, map 제외)
+func fieldsOf(config interface{}) []field {
+	fields := make([]field, 0)
+	collectFields(&fields, "", "", reflect.ValueOf(config).Elem())
+	return fields
+}
+
+func collectFields(fields *[]field, path, envPrefix string, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := yamlName(f)
+		if !ok {
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		env := f.Tag.Get("env")
+		if env != "" && envPrefix != "" {
+			env = envPrefix + "_" + env
+		}
+
+		value := v.Field(i)
+		switch {
+		case value.Type() == durationType:
+		case value.Kind() == reflect.Struct:
+			collectFields(fields, fieldPath, env, value)
+			continue
+		case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct,
+			value.Kind() == reflect.Map, value.Kind() == reflect.Pointer:
+			continue
+		}
+
+		*fields = append(*fields, field{
+			path:     fieldPath,
+			env:      env,
+			def:      f.Tag.Get("default"),
+			required: f.Tag.Get("required") == "true",
+			value:    value,
+		})
+	}
+}
+
+// setValue 문자열을 필드 타입으로 변환하여 설정
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		parts := strings.Split(s, ",")
+		slice := reflect.MakeSlice(v.Type(), 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			item := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(item, part); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		v.Set(slice)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// isEmpty 값이 없는지 여부 (zero value 또는 빈 목록)
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice {
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// flagValue 설정 필드 flag 값 (타입 변환은 Load에서 처리)
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
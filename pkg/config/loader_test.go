@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type loaderConfig struct {
+	Kafka struct {
+		Broker  []string      `yaml:"broker" env:"KAFKA_BROKER" required:"true"`
+		Topic   string        `yaml:"topic" env:"KAFKA_TOPIC" required:"true"`
+		Timeout time.Duration `yaml:"timeout" env:"KAFKA_TIMEOUT" default:"10s"`
+		Retry   int           `yaml:"retry" env:"KAFKA_RETRY" default:"3"`
+		SASL    struct {
+			Enabled  bool   `yaml:"enabled" env:"ENABLED"`
+			Password string `yaml:"password" env:"PASSWORD" secret:"true"`
+		} `yaml:"sasl" env:"KAFKA_SASL"`
+	} `yaml:"kafka"`
+	Index string `yaml:"index" env:"ELASTIC_INDEX" required:"true"`
+}
+
+func TestLoader(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	data := []byte(`
+kafka:
+  broker:
+    - kafka:9092
+  topic: file-topic
+  retry: 5
+index: file-index
+`)
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("KAFKA_TOPIC", "env-topic")
+	t.Setenv("KAFKA_SASL_PASSWORD", "secret")
+	t.Setenv("ELASTIC_INDEX", "env-index")
+
+	config := &loaderConfig{}
+	loader, err := NewLoader("test", config, []string{
+		"--config", file,
+		"--print-config",
+		"--index=flag-index",
+		"--kafka.sasl.enabled",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loader.Mode() != ModePrint {
+		t.Errorf("Mode() = %v, want ModePrint", loader.Mode())
+	}
+
+	sources, err := loader.Load(config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// default < file < env < flag
+	want := map[string]struct {
+		value  string
+		source Source
+	}{
+		"kafka.broker":        {"kafka:9092", SourceFile},
+		"kafka.topic":         {"env-topic", SourceEnv},
+		"kafka.timeout":       {"10s", SourceDefault},
+		"kafka.retry":         {"5", SourceFile},
+		"kafka.sasl.enabled":  {"true", SourceFlag},
+		"kafka.sasl.password": {Redacted, SourceEnv},
+		"index":               {"flag-index", SourceFlag},
+	}
+	for _, field := range Fields(config, sources) {
+		w := want[field.Path]
+		if field.Value != w.value || field.Source != w.source {
+			t.Errorf("%s = %s (%s), want %s (%s)", field.Path, field.Value, field.Source, w.value, w.source)
+		}
+	}
+
+	var out bytes.Buffer
+	if code := loader.Report(&out, config, sources, nil); code != 0 {
+		t.Errorf("Report() = %d, want 0", code)
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Errorf("Report() printed secret: %s", out.String())
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	t.Setenv("KAFKA_TIMEOUT", "ten")
+	t.Setenv("KAFKA_SASL_ENABLED", "yes")
+
+	config := &loaderConfig{}
+	loader, err := NewLoader("test", config, []string{"--validate-config", "--kafka.retry=x"}, WithDefaultFile(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = loader.Load(config, nil)
+	if err == nil {
+		t.Fatal("Load() error = nil")
+	}
+
+	// 모든 에러를 한 번에 보고
+	var out bytes.Buffer
+	if code := loader.Report(&out, config, nil, err); code != 1 {
+		t.Errorf("Report() = %d, want 1", code)
+	}
+	for _, want := range []string{
+		"env KAFKA_TIMEOUT",
+		"env KAFKA_SASL_ENABLED",
+		"flag --kafka.retry",
+		"config kafka.broker required",
+		"config kafka.topic required",
+		"config index required",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Report() = %s, want %s", out.String(), want)
+		}
+	}
+
+	if _, err := NewLoader("test", config, []string{"--unknown"}, WithOutput(&out)); err == nil {
+		t.Errorf("NewLoader() error = nil, want unknown flag error")
+	}
+}
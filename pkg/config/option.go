@@ -0,0 +1,61 @@
+package config
+
+import (
+	"io"
+	"os"
+)
+
+const (
+	// EnvConfigFile 설정 파일 경로 환경변수 (--config가 없을 때 사용)
+	EnvConfigFile string = "CONFIG_FILE"
+
+	DefaultFile string = "/etc/stradvision/config.yaml"
+)
+
+type config struct {
+	file   string    // --config 기본값
+	output io.Writer // 사용법 출력
+}
+
+type Option func(*config)
+
+func defaultConfig() *config {
+	file := DefaultFile
+	if env := os.Getenv(EnvConfigFile); env != "" {
+		file = env
+	}
+
+	return &config{
+		file:   file,
+		output: os.Stderr,
+	}
+}
+
+func fromOptions(options ...Option) *config {
+	c := defaultConfig()
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithDefaultFile --config, CONFIG_FILE이 없을 때 읽을 설정 파일 경로 설정
+// file: 빈 문자열이면 설정 파일을 읽지 않음
+func WithDefaultFile(file string) Option {
+	return func(c *config) {
+		if os.Getenv(EnvConfigFile) == "" {
+			c.file = file
+		}
+	}
+}
+
+// WithOutput 사용법, flag 에러 출력 설정 (기본값 stderr)
+func WithOutput(w io.Writer) Option {
+	if w == nil {
+		return func(c *config) {}
+	}
+	return func(c *config) {
+		c.output = w
+	}
+}
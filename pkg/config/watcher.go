@@ -0,0 +1,95 @@
+package config
+
+import (
+	"crypto/sha256"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Watcher 설정 파일 변경과 SIGHUP을 감지하여 onChange 호출
+// ConfigMap volume은 ..data symlink를 교체하여 파일을 갱신하므로
+// inotify 대신 symlink를 따라 읽은 파일 내용을 주기적으로 비교하여 감지
+type Watcher struct {
+	file     string // 비어있으면 SIGHUP만 감지
+	interval time.Duration
+	onChange func()
+
+	sum       [sha256.Size]byte // 마지막으로 읽은 파일 내용
+	closeChan chan struct{}
+	doneChan  chan struct{}
+	closeOnce sync.Once
+	running   atomic.Bool
+}
+
+// NewWatcher 설정 파일 watcher 생성
+// onChange는 Run 루프에서 호출되므로 끝날 때까지 다음 변경을 감지하지 않음
+func NewWatcher(file string, onChange func(), options ...WatcherOption) *Watcher {
+	c := fromWatcherOptions(options)
+
+	w := &Watcher{
+		file:      file,
+		interval:  c.interval,
+		onChange:  onChange,
+		closeChan: make(chan struct{}),
+		doneChan:  make(chan struct{}),
+	}
+	w.sum, _ = w.read()
+
+	return w
+}
+
+// Run 파일 내용이 바뀌거나 SIGHUP을 받으면 onChange 호출
+// 파일을 읽을 수 없는 동안(symlink 교체 중 등)은 변경으로 보지 않음
+func (w *Watcher) Run() {
+	w.running.Store(true)
+	defer close(w.doneChan)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.closeChan:
+			return
+		case <-sigChan:
+			w.sum, _ = w.read()
+			w.onChange()
+		case <-ticker.C:
+			sum, ok := w.read()
+			if !ok || sum == w.sum {
+				continue
+			}
+			w.sum = sum
+			w.onChange()
+		}
+	}
+}
+
+// Close 감지를 멈추고 실행 중인 onChange가 끝날 때까지 대기
+func (w *Watcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.closeChan)
+	})
+	if w.running.Load() {
+		<-w.doneChan
+	}
+}
+
+func (w *Watcher) read() ([sha256.Size]byte, bool) {
+	if w.file == "" {
+		return [sha256.Size]byte{}, false
+	}
+	data, err := os.ReadFile(w.file)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(data), true
+}
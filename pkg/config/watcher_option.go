@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+const (
+	// DefaultWatchInterval 설정 파일 변경 확인 주기
+	DefaultWatchInterval = 5 * time.Second
+)
+
+type watcherConfig struct {
+	interval time.Duration
+}
+
+type WatcherOption func(*watcherConfig)
+
+func fromWatcherOptions(options []WatcherOption) *watcherConfig {
+	c := &watcherConfig{interval: DefaultWatchInterval}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithWatchInterval 설정 파일 변경 확인 주기 설정
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(c *watcherConfig) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
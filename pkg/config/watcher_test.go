@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestWatcher ConfigMap volume과 같이 ..data symlink를 교체하여 갱신한 파일 감지
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeVersion := func(version, data string) {
+		if err := os.Mkdir(filepath.Join(dir, version), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "config.yaml"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tmp := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(version, tmp); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeVersion("..v1", "index: event\n")
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(filepath.Join("..data", "config.yaml"), file); err != nil {
+		t.Fatal(err)
+	}
+
+	changed := make(chan struct{}, 10)
+	w := NewWatcher(file, func() { changed <- struct{}{} }, WithWatchInterval(10*time.Millisecond))
+	go w.Run()
+	defer w.Close()
+
+	select {
+	case <-changed:
+		t.Fatal("onChange called without change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeVersion("..v2", "index: event-v2\n")
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("onChange not called after symlink swap")
+	}
+
+	// SIGHUP은 내용이 같아도 reload
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("onChange not called after SIGHUP")
+	}
+}
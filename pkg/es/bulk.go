@@ -0,0 +1,119 @@
+package es
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	// ErrorTypeRequestFailed bulk 요청 자체가 실패하여 응답을 받지 못한 문서의 ErrorType
+	ErrorTypeRequestFailed string = "request_failed"
+)
+
+// BulkItem bulk 요청의 문서별 처리 결과
+type BulkItem struct {
+	Position    int    // bulk 요청 내 문서 순서 (0부터 시작)
+	Action      string // index, create, update, delete
+	Index       string
+	ID          string
+	Status      int
+	ErrorType   string
+	ErrorReason string
+}
+
+// IsFailed 문서 처리 실패 여부
+// create, external version index의 충돌은 같거나 더 최신 문서가 이미 저장된 상태이므로 성공으로 처리
+func (i BulkItem) IsFailed() bool {
+	if i.IsVersionConflict() && i.Action != ActionUpdate {
+		return false
+	}
+
+	return i.ErrorType != "" || i.Status >= 300
+}
+
+// IsVersionConflict 같은 ID의 문서와 충돌했는지 확인
+func (i BulkItem) IsVersionConflict() bool {
+	return i.Status == http.StatusConflict
+}
+
+// BulkResult bulk 요청 처리 결과
+type BulkResult struct {
+	Took      int
+	Succeeded []BulkItem
+	Failed    []BulkItem
+}
+
+// HasFailed 실패한 문서가 있는지 확인
+func (r *BulkResult) HasFailed() bool {
+	return len(r.Failed) > 0
+}
+
+// FailedPositions 실패한 문서의 bulk 요청 내 순서 목록
+func (r *BulkResult) FailedPositions() []int {
+	positions := make([]int, 0, len(r.Failed))
+	for _, item := range r.Failed {
+		positions = append(positions, item.Position)
+	}
+
+	return positions
+}
+
+type bulkResponse struct {
+	Took   int                           `json:"took"`
+	Errors bool                          `json:"errors"`
+	Items  []map[string]bulkResponseItem `json:"items"`
+}
+
+type bulkResponseItem struct {
+	Index  string `json:"_index"`
+	ID     string `json:"_id"`
+	Status int    `json:"status"`
+	Error  *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// parseBulkResponse bulk 응답을 문서별 처리 결과로 변환
+func parseBulkResponse(body io.Reader) (*BulkResult, error) {
+	res := &bulkResponse{}
+	if err := json.NewDecoder(body).Decode(res); err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{
+		Took:      res.Took,
+		Succeeded: make([]BulkItem, 0, len(res.Items)),
+		Failed:    make([]BulkItem, 0),
+	}
+	for position, entry := range res.Items {
+		// 각 item은 action 이름을 key로 하는 단일 객체
+		if len(entry) != 1 {
+			return nil, fmt.Errorf("invalid bulk response item at %d", position)
+		}
+
+		for action, value := range entry {
+			item := BulkItem{
+				Position: position,
+				Action:   action,
+				Index:    value.Index,
+				ID:       value.ID,
+				Status:   value.Status,
+			}
+			if value.Error != nil {
+				item.ErrorType = value.Error.Type
+				item.ErrorReason = value.Error.Reason
+			}
+
+			if item.IsFailed() {
+				result.Failed = append(result.Failed, item)
+			} else {
+				result.Succeeded = append(result.Succeeded, item)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package es
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBulkResponse(t *testing.T) {
+	body := `{
+		"took": 30,
+		"errors": true,
+		"items": [
+			{"index": {"_index": "event", "_id": "1", "status": 201}},
+			{"index": {"_index": "event", "_id": "2", "status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse field [eventTime]"}}},
+			{"index": {"_index": "event", "_id": "3", "status": 429, "error": {"type": "es_rejected_execution_exception", "reason": "rejected execution"}}}
+		]
+	}`
+
+	result, err := parseBulkResponse(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parseBulkResponse() error = %v, want %v", err, nil)
+	}
+
+	if len(result.Succeeded) != 1 {
+		t.Errorf("parseBulkResponse() succeeded = %d, want %d", len(result.Succeeded), 1)
+	}
+	if !result.HasFailed() || len(result.Failed) != 2 {
+		t.Fatalf("parseBulkResponse() failed = %d, want %d", len(result.Failed), 2)
+	}
+
+	positions := result.FailedPositions()
+	if positions[0] != 1 || positions[1] != 2 {
+		t.Errorf("FailedPositions() = %v, want %v", positions, []int{1, 2})
+	}
+	if result.Failed[1].Status != 429 || result.Failed[1].ErrorType != "es_rejected_execution_exception" {
+		t.Errorf("parseBulkResponse() failed item = %+v", result.Failed[1])
+	}
+}
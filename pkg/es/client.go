@@ -3,27 +3,34 @@ package es
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"example.com/stradvision-project/pkg/metrics"
 	"github.com/elastic/go-elasticsearch/v8"
 )
 
 type Client struct {
-	es *elasticsearch.Client
+	es    *elasticsearch.Client
+	retry retryPolicy
 }
 
-func NewElasticsearchClient(addrs []string, user, pass string) (*Client, error) {
-	config := elasticsearch.Config{
-		Addresses: addrs,
-		Username:  user,
-		Password:  pass,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true, // TLS 인증서 검증 비활성화
-			},
-		},
+// NewElasticsearchClient elasticsearch client 생성
+// user, pass: basic 인증 (API key, service token을 설정하면 사용하지 않음)
+func NewElasticsearchClient(addrs []string, user, pass string, options ...Option) (*Client, error) {
+	c := fromOptions(options...)
+
+	config, err := c.esConfig(addrs, user, pass)
+	if err != nil {
+		return nil, err
 	}
 
 	es, err := elasticsearch.NewClient(config)
@@ -32,21 +39,271 @@ func NewElasticsearchClient(addrs []string, user, pass string) (*Client, error)
 	}
 
 	return &Client{
-		es: es,
+		es:    es,
+		retry: c.retry,
 	}, nil
 }
 
-func (c *Client) WriteBulk(index string, data []byte) error {
-	buf := bytes.NewBuffer(data)
-	res, err := c.es.Bulk(buf, c.es.Bulk.WithContext(context.Background()))
+// esConfig 인증, TLS 설정으로 elasticsearch 설정 생성 (secret 파일을 읽음)
+func (c *config) esConfig(addrs []string, user, pass string) (elasticsearch.Config, error) {
+	config := elasticsearch.Config{
+		Username:     user,
+		Password:     pass,
+		APIKey:       c.apiKey,
+		ServiceToken: c.serviceToken,
+	}
+	if c.cloudID != "" {
+		config.CloudID = c.cloudID
+	} else {
+		config.Addresses = addrs
+	}
+
+	var err error
+	if c.passwordFile != "" {
+		if config.Password, err = readSecret(c.passwordFile); err != nil {
+			return config, fmt.Errorf("failed to read password file: %w", err)
+		}
+	}
+	if c.apiKeyFile != "" {
+		if config.APIKey, err = readSecret(c.apiKeyFile); err != nil {
+			return config, fmt.Errorf("failed to read api key file: %w", err)
+		}
+	}
+	if c.serviceTokenFile != "" {
+		if config.ServiceToken, err = readSecret(c.serviceTokenFile); err != nil {
+			return config, fmt.Errorf("failed to read service token file: %w", err)
+		}
+	}
+	if c.caCertFile != "" {
+		if config.CACert, err = os.ReadFile(c.caCertFile); err != nil {
+			return config, fmt.Errorf("failed to read ca cert file: %w", err)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.insecureSkipVerify,
+	}
+	if c.clientCertFile != "" || c.clientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.clientCertFile, c.clientKeyFile)
+		if err != nil {
+			return config, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	// elasticsearch transport의 fingerprint 검증은 TLS 설정을 교체하여 클라이언트 인증서가 빠지므로 직접 검증
+	if c.certificateFingerprint != "" {
+		fingerprint, err := ParseFingerprint(c.certificateFingerprint)
+		if err != nil {
+			return config, err
+		}
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyConnection = verifyFingerprint(fingerprint)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	config.Transport = transport
+
+	return config, nil
+}
+
+// ParseFingerprint 인증서 SHA-256 fingerprint(hex, ':' 구분 가능)를 bytes로 변환
+func ParseFingerprint(fingerprint string) ([]byte, error) {
+	digest, err := hex.DecodeString(strings.ReplaceAll(fingerprint, ":", ""))
+	if err != nil {
+		return nil, fmt.Errorf("certificate fingerprint invalid: %w", err)
+	}
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("certificate fingerprint invalid: SHA-256 digest must be %d bytes, got %d", sha256.Size, len(digest))
+	}
+
+	return digest, nil
+}
+
+// verifyFingerprint 서버 인증서 체인에 fingerprint가 일치하는 인증서가 있는지 확인
+func verifyFingerprint(fingerprint []byte) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		for _, cert := range state.PeerCertificates {
+			digest := sha256.Sum256(cert.Raw)
+			if bytes.Equal(digest[:], fingerprint) {
+				return nil
+			}
+		}
+		return fmt.Errorf("elasticsearch certificate fingerprint mismatch: %x", fingerprint)
+	}
+}
+
+// readSecret mount된 secret 파일을 읽음 (앞뒤 공백, 개행 제거)
+func readSecret(file string) (string, error) {
+	data, err := os.ReadFile(file)
 	if err != nil {
-		return fmt.Errorf("failed to send elasticsearch bulk request: %w", err)
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Ping elasticsearch 연결 상태 확인
+func (c *Client) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	res, err := c.es.Ping(c.es.Ping.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to ping elasticsearch: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return fmt.Errorf("elasticsearch bulk request failed: %s", res.String())
+		return &ResponseError{StatusCode: res.StatusCode, Message: res.String()}
 	}
 
 	return nil
 }
+
+// WriteBulk bulk 요청 전송
+// 요청 자체가 실패하면 error를 반환하고, 문서별 실패는 BulkResult.Failed로 반환
+func (c *Client) WriteBulk(ctx context.Context, index string, data []byte) (*BulkResult, error) {
+	buf := bytes.NewBuffer(data)
+	res, err := c.es.Bulk(buf, c.es.Bulk.WithContext(ctx))
+	if err != nil {
+		metrics.ESBulkRequestFailed.Inc()
+		return nil, fmt.Errorf("failed to send elasticsearch bulk request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		metrics.ESBulkRequestFailed.Inc()
+		return nil, &ResponseError{StatusCode: res.StatusCode, Message: res.String()}
+	}
+
+	result, err := parseBulkResponse(res.Body)
+	if err != nil {
+		metrics.ESBulkRequestFailed.Inc()
+		return nil, fmt.Errorf("failed to decode elasticsearch bulk response: %w", err)
+	}
+
+	metrics.ESBulkIndexed.Add(float64(len(result.Succeeded)))
+	for _, item := range result.Failed {
+		metrics.ESBulkFailed.WithLabelValues(strconv.Itoa(item.Status)).Inc()
+	}
+
+	return result, nil
+}
+
+// WriteBulkWithRetry 문서 단위(action + source)로 bulk 요청 전송
+// 재시도 가능한 실패는 해당 문서만 backoff 후 재전송하고, 재시도 횟수를 모두 소진하거나
+// 영구적인 실패인 문서는 BulkResult.Failed로 반환 (Position은 items의 순서)
+// ctx가 취소되면 재시도를 기다리지 않고 남은 문서를 요청 실패로 반환
+func (c *Client) WriteBulkWithRetry(ctx context.Context, index string, items [][]byte) *BulkResult {
+	result := &BulkResult{
+		Succeeded: make([]BulkItem, 0, len(items)),
+		Failed:    make([]BulkItem, 0),
+	}
+
+	pending := make([]int, len(items))
+	for i := range items {
+		pending[i] = i
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		lastAttempt := attempt >= c.retry.maxAttempts
+
+		data := make([]byte, 0)
+		for _, position := range pending {
+			data = append(data, items[position]...)
+		}
+
+		res, err := c.WriteBulk(ctx, index, data)
+		if err != nil {
+			// 요청 자체가 실패하면 남은 문서 전체를 재시도
+			if lastAttempt || !IsRetryableError(err) || ctx.Err() != nil {
+				result.Failed = append(result.Failed, requestFailed(pending, err)...)
+				break
+			}
+
+			metrics.ESBulkRetried.Add(float64(len(pending)))
+			if err := c.wait(ctx, attempt); err != nil {
+				result.Failed = append(result.Failed, requestFailed(pending, err)...)
+				break
+			}
+			continue
+		}
+
+		result.Took += res.Took
+		// 응답 항목의 Position은 이번 요청에서의 순서이므로 items의 순서로 변환
+		// 보낸 문서보다 많은 응답 항목은 무시하고, 응답이 없는 문서는 재시도
+		answered := make([]bool, len(pending))
+		for _, item := range res.Succeeded {
+			if item.Position < 0 || item.Position >= len(pending) {
+				continue
+			}
+			answered[item.Position] = true
+			item.Position = pending[item.Position]
+			result.Succeeded = append(result.Succeeded, item)
+		}
+
+		retry := make([]int, 0)
+		for _, item := range res.Failed {
+			if item.Position < 0 || item.Position >= len(pending) {
+				continue
+			}
+			answered[item.Position] = true
+			item.Position = pending[item.Position]
+			if !lastAttempt && c.retry.classifier(item) {
+				retry = append(retry, item.Position)
+				continue
+			}
+			result.Failed = append(result.Failed, item)
+		}
+
+		missing := make([]int, 0)
+		for i, ok := range answered {
+			if !ok {
+				missing = append(missing, pending[i])
+			}
+		}
+		if lastAttempt {
+			result.Failed = append(result.Failed, requestFailed(missing, errors.New("missing in elasticsearch bulk response"))...)
+		} else {
+			retry = append(retry, missing...)
+		}
+
+		pending = retry
+		if len(pending) > 0 {
+			metrics.ESBulkRetried.Add(float64(len(pending)))
+			if err := c.wait(ctx, attempt); err != nil {
+				result.Failed = append(result.Failed, requestFailed(pending, err)...)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// wait attempt번째 시도 후 재시도 간격만큼 대기 (ctx가 취소되면 ctx 에러 반환)
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(c.retry.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestFailed 요청 실패로 결과를 받지 못한 문서
+func requestFailed(positions []int, err error) []BulkItem {
+	items := make([]BulkItem, 0, len(positions))
+	for _, position := range positions {
+		items = append(items, BulkItem{
+			Position:    position,
+			ErrorType:   ErrorTypeRequestFailed,
+			ErrorReason: err.Error(),
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,86 @@
+package es
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestESConfig(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	addrs := []string{"https://elasticsearch:9200"}
+
+	// 기본값은 인증서 검증
+	config, err := fromOptions().esConfig(addrs, "elastic", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("InsecureSkipVerify = true, want false by default")
+	}
+
+	config, err = fromOptions(
+		WithPasswordFile(passwordFile),
+		WithAPIKey("key"),
+		WithInsecureSkipVerify(true),
+	).esConfig(addrs, "elastic", "pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Password != "secret" || config.APIKey != "key" {
+		t.Errorf("config = %s, %s", config.Password, config.APIKey)
+	}
+	if !config.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("InsecureSkipVerify = false, want true")
+	}
+
+	// fingerprint는 TLS 설정에서 직접 검증 (elasticsearch transport가 TLS 설정을 교체하지 않도록)
+	cert := &x509.Certificate{Raw: []byte("ca")}
+	digest := sha256.Sum256(cert.Raw)
+	fingerprint := strings.ToUpper(hex.EncodeToString(digest[:]))
+	config, err = fromOptions(WithCertificateFingerprint(fingerprint[:2]+":"+fingerprint[2:])).esConfig(addrs, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := config.Transport.(*http.Transport).TLSClientConfig
+	if config.CertificateFingerprint != "" || tlsConfig.VerifyConnection == nil {
+		t.Fatalf("fingerprint not verified by TLS config")
+	}
+	if err := tlsConfig.VerifyConnection(tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("leaf")}, cert}}); err != nil {
+		t.Errorf("VerifyConnection() error = %v", err)
+	}
+	if err := tlsConfig.VerifyConnection(tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("other")}}}); err == nil {
+		t.Errorf("VerifyConnection() error = nil, want mismatch")
+	}
+
+	config, err = fromOptions(WithCloudID("cloud:id")).esConfig(addrs, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CloudID != "cloud:id" || len(config.Addresses) != 0 {
+		t.Errorf("cloudID = %s, addresses = %v", config.CloudID, config.Addresses)
+	}
+
+	for _, option := range []Option{
+		WithPasswordFile(filepath.Join(dir, "none")),
+		WithAPIKeyFile(filepath.Join(dir, "none")),
+		WithCACertFile(filepath.Join(dir, "none")),
+		WithClientCertFile(filepath.Join(dir, "none"), ""),
+		WithCertificateFingerprint("AB:CD"),
+	} {
+		if _, err := fromOptions(option).esConfig(addrs, "", ""); err == nil {
+			t.Errorf("esConfig() error = nil, want error")
+		}
+	}
+}
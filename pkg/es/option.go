@@ -0,0 +1,168 @@
+package es
+
+import (
+	"time"
+)
+
+type config struct {
+	retry retryPolicy
+
+	// 인증 (apiKey > serviceToken > basic 순서로 사용)
+	passwordFile     string
+	apiKey           string
+	apiKeyFile       string
+	serviceToken     string
+	serviceTokenFile string
+	cloudID          string // 설정하면 주소 대신 사용
+
+	// TLS (기본값은 시스템 CA로 인증서 검증)
+	caCertFile             string
+	certificateFingerprint string
+	clientCertFile         string
+	clientKeyFile          string
+	insecureSkipVerify     bool
+}
+
+type Option func(*config)
+
+func defaultConfig() *config {
+	return &config{
+		retry: retryPolicy{
+			maxAttempts: 3,                      // 최초 요청 포함 최대 3회
+			baseBackoff: 500 * time.Millisecond, // 재시도 간격 0.5초부터 2배씩 증가
+			maxBackoff:  10 * time.Second,       // 최대 재시도 간격 10초
+			jitter:      0.2,                    // 재시도 간격의 ±20%
+			classifier:  IsRetryableItem,
+		},
+	}
+}
+
+func fromOptions(options ...Option) *config {
+	c := defaultConfig()
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// WithRetryMax 최초 요청을 포함한 최대 시도 횟수 설정
+// max: 최소값 1 (1이면 재시도하지 않음)
+func WithRetryMax(max int) Option {
+	return func(c *config) {
+		if max > 0 {
+			c.retry.maxAttempts = max
+		}
+	}
+}
+
+// WithRetryBackoff 첫 재시도 간격 설정
+func WithRetryBackoff(backoff time.Duration) Option {
+	return func(c *config) {
+		if backoff > 0 {
+			c.retry.baseBackoff = backoff
+		}
+	}
+}
+
+// WithRetryMaxBackoff 최대 재시도 간격 설정
+func WithRetryMaxBackoff(backoff time.Duration) Option {
+	return func(c *config) {
+		if backoff > 0 {
+			c.retry.maxBackoff = backoff
+		}
+	}
+}
+
+// WithRetryJitter 재시도 간격의 무작위 편차 비율 설정
+// jitter: 0 이상 1 이하 (0이면 편차 없이 재시도)
+func WithRetryJitter(jitter float64) Option {
+	return func(c *config) {
+		if jitter >= 0 && jitter <= 1 {
+			c.retry.jitter = jitter
+		}
+	}
+}
+
+// WithRetryClassifier 재시도 가능한 문서 실패를 판단하는 함수 설정
+func WithRetryClassifier(classifier func(BulkItem) bool) Option {
+	return func(c *config) {
+		if classifier != nil {
+			c.retry.classifier = classifier
+		}
+	}
+}
+
+// WithPasswordFile basic 인증 password를 파일에서 읽음 (password보다 우선)
+// 파일은 client 생성 시 읽음
+func WithPasswordFile(file string) Option {
+	return func(c *config) {
+		c.passwordFile = file
+	}
+}
+
+// WithAPIKey API key 인증 설정 (base64 인코딩된 id:api_key)
+func WithAPIKey(apiKey string) Option {
+	return func(c *config) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithAPIKeyFile API key를 파일에서 읽음 (apiKey보다 우선)
+func WithAPIKeyFile(file string) Option {
+	return func(c *config) {
+		c.apiKeyFile = file
+	}
+}
+
+// WithServiceToken bearer token(service account token) 인증 설정
+func WithServiceToken(token string) Option {
+	return func(c *config) {
+		c.serviceToken = token
+	}
+}
+
+// WithServiceTokenFile bearer token을 파일에서 읽음 (token보다 우선)
+func WithServiceTokenFile(file string) Option {
+	return func(c *config) {
+		c.serviceTokenFile = file
+	}
+}
+
+// WithCloudID Elastic Cloud ID 설정
+// 설정하면 주소 대신 cloud ID의 endpoint 사용
+func WithCloudID(cloudID string) Option {
+	return func(c *config) {
+		c.cloudID = cloudID
+	}
+}
+
+// WithCACertFile 서버 인증서 검증에 사용할 CA 인증서 파일 설정 (없으면 시스템 CA)
+func WithCACertFile(file string) Option {
+	return func(c *config) {
+		c.caCertFile = file
+	}
+}
+
+// WithCertificateFingerprint 서버 CA 인증서의 SHA-256 fingerprint(hex) 설정
+// Elasticsearch 8 최초 실행 시 출력되는 fingerprint로 자체 서명 인증서를 검증
+func WithCertificateFingerprint(fingerprint string) Option {
+	return func(c *config) {
+		c.certificateFingerprint = fingerprint
+	}
+}
+
+// WithClientCertFile mTLS 클라이언트 인증서와 키 파일 설정
+func WithClientCertFile(certFile, keyFile string) Option {
+	return func(c *config) {
+		c.clientCertFile = certFile
+		c.clientKeyFile = keyFile
+	}
+}
+
+// WithInsecureSkipVerify 서버 인증서 검증 비활성화 (테스트 환경에서만 사용)
+func WithInsecureSkipVerify(insecure bool) Option {
+	return func(c *config) {
+		c.insecureSkipVerify = insecure
+	}
+}
@@ -0,0 +1,73 @@
+package es
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"example.com/stradvision-project/pkg/backoff"
+)
+
+type retryPolicy struct {
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+	classifier  func(BulkItem) bool
+}
+
+// backoff attempt번째 시도가 실패한 뒤 대기할 시간
+// baseBackoff부터 2배씩 증가하며 maxBackoff를 넘지 않음
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	return backoff.Exponential(attempt, p.baseBackoff, p.maxBackoff, p.jitter)
+}
+
+// ResponseError elasticsearch가 에러 상태 코드로 응답한 경우의 에러
+type ResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	return "elasticsearch bulk request failed: " + e.Message
+}
+
+// IsRetryableStatus 일시적인 장애를 나타내는 상태 코드인지 확인
+func IsRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryableItem 문서 실패가 재시도로 해결될 수 있는지 확인
+// 429(queue full), 503(shard unavailable) 등은 재시도, mapping 충돌 등은 영구 실패
+func IsRetryableItem(item BulkItem) bool {
+	switch item.ErrorType {
+	case "es_rejected_execution_exception",
+		"circuit_breaking_exception",
+		"unavailable_shards_exception",
+		"no_shard_available_action_exception",
+		"node_not_connected_exception",
+		"process_cluster_event_timeout_exception":
+		return true
+	}
+
+	return IsRetryableStatus(item.Status)
+}
+
+// IsRetryableError bulk 요청 자체의 실패가 재시도로 해결될 수 있는지 확인
+// 에러 응답은 상태 코드로 판단하고, 연결 끊김 등 전송 에러는 재시도
+func IsRetryableError(err error) bool {
+	var resErr *ResponseError
+	if errors.As(err, &resErr) {
+		return IsRetryableStatus(resErr.StatusCode)
+	}
+
+	return err != nil
+}
@@ -0,0 +1,100 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteBulkWithRetry(t *testing.T) {
+	// 첫 요청: 0번 성공, 1번 mapping 실패(영구), 2번 queue full(재시도)
+	// 두 번째 요청: 2번만 재전송되어 성공
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		if len(requests) == 1 {
+			fmt.Fprint(w, `{"took":1,"errors":true,"items":[
+				{"index":{"_index":"event","status":201}},
+				{"index":{"_index":"event","status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}},
+				{"index":{"_index":"event","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected execution"}}}
+			]}`)
+			return
+		}
+		fmt.Fprint(w, `{"took":1,"errors":false,"items":[{"index":{"_index":"event","status":201}}]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewElasticsearchClient([]string{server.URL}, "", "",
+		WithRetryMax(3),
+		WithRetryBackoff(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := make([][]byte, 0)
+	for i := 0; i < 3; i++ {
+		item, _ := ConvertTemplate("event", map[string]int{"doc": i})
+		items = append(items, item)
+	}
+
+	result := client.WriteBulkWithRetry(context.Background(), "event", items)
+	if len(requests) != 2 {
+		t.Fatalf("WriteBulkWithRetry() requests = %d, want %d", len(requests), 2)
+	}
+	if !strings.Contains(requests[1], `"doc":2`) || strings.Contains(requests[1], `"doc":1`) {
+		t.Errorf("WriteBulkWithRetry() resent = %s", requests[1])
+	}
+	if len(result.Succeeded) != 2 {
+		t.Errorf("WriteBulkWithRetry() succeeded = %d, want %d", len(result.Succeeded), 2)
+	}
+	if positions := result.FailedPositions(); len(positions) != 1 || positions[0] != 1 {
+		t.Errorf("WriteBulkWithRetry() failed = %v, want %v", positions, []int{1})
+	}
+}
+
+func TestWriteBulkWithRetryCanceled(t *testing.T) {
+	// 보낸 문서보다 많은 응답 항목은 무시하고, 재시도 대기 중 ctx가 취소되면 바로 반환
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"took":1,"errors":true,"items":[
+			{"index":{"_index":"event","status":429,"error":{"type":"es_rejected_execution_exception","reason":"rejected execution"}}},
+			{"index":{"_index":"event","status":201}}
+		]}`)
+	}))
+	defer server.Close()
+
+	client, err := NewElasticsearchClient([]string{server.URL}, "", "",
+		WithRetryBackoff(time.Hour),
+		WithRetryJitter(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item, _ := ConvertTemplate("event", map[string]int{"doc": 0})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result := client.WriteBulkWithRetry(ctx, "event", [][]byte{item})
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WriteBulkWithRetry() took %v after ctx canceled", elapsed)
+	}
+	if len(result.Succeeded) != 0 {
+		t.Errorf("WriteBulkWithRetry() succeeded = %d, want 0", len(result.Succeeded))
+	}
+	if len(result.Failed) != 1 || result.Failed[0].ErrorType != ErrorTypeRequestFailed {
+		t.Errorf("WriteBulkWithRetry() failed = %+v, want request failed", result.Failed)
+	}
+}
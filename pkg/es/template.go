@@ -7,9 +7,40 @@ import (
 )
 
 const (
-	template = `{"index": {"_index": "%s"}}\n`
+	template = "{\"index\": {\"_index\": \"%s\"}}\n"
 )
 
+const (
+	// bulk 요청 action
+	ActionIndex  string = "index"  // 문서 저장, 같은 ID가 있으면 덮어씀
+	ActionCreate string = "create" // 같은 ID가 없을 때만 문서 저장
+	ActionUpdate string = "update" // 같은 ID의 문서를 갱신하고, 없으면 저장 (upsert)
+
+	// DefaultRetryOnConflict update 요청의 문서 충돌 시 재시도 횟수
+	DefaultRetryOnConflict int = 3
+)
+
+// BulkAction bulk 요청의 action 정보
+type BulkAction struct {
+	Action  string // index, create, update (기본값 index)
+	Index   string
+	ID      string // 없으면 elasticsearch에서 자동 생성
+	Version int64  // index action에서 0보다 크면 external version으로 사용
+}
+
+type actionMeta struct {
+	Index           string `json:"_index"`
+	ID              string `json:"_id,omitempty"`
+	Version         int64  `json:"version,omitempty"`
+	VersionType     string `json:"version_type,omitempty"`
+	RetryOnConflict int    `json:"retry_on_conflict,omitempty"`
+}
+
+type upsertDoc struct {
+	Doc         interface{} `json:"doc"`
+	DocAsUpsert bool        `json:"doc_as_upsert"`
+}
+
 func ConvertTemplate(index string, doc interface{}) ([]byte, error) {
 	data, err := json.Marshal(doc)
 	if err != nil {
@@ -37,3 +68,66 @@ func ConvertTemplates(index string, docs []interface{}) ([]byte, error) {
 
 	return result, nil
 }
+
+// CheckAction bulk action 설정 확인
+// update는 version을 비교하지 않고 덮어쓰므로, 같은 ID를 여러 버전이 공유하면
+// 다시 처리한 오래된 문서가 최신 문서를 덮어쓸 수 있어 버전별 ID(versionedID)만 허용
+func CheckAction(action string, versionedID bool) error {
+	switch action {
+	case "", ActionIndex, ActionCreate:
+	case ActionUpdate:
+		if !versionedID {
+			return fmt.Errorf("versioned documentID required for update action")
+		}
+	default:
+		return fmt.Errorf("action invalid: %s", action)
+	}
+
+	return nil
+}
+
+// ConvertAction action 정보와 문서를 bulk 요청 형식으로 변환
+// index action에 Version을 지정하면 external_gte version으로 저장하여
+// 같은 ID의 더 오래된 문서가 최신 문서를 덮어쓰지 않도록 함
+func ConvertAction(action BulkAction, doc interface{}) ([]byte, error) {
+	meta := actionMeta{
+		Index: action.Index,
+		ID:    action.ID,
+	}
+
+	var source interface{} = doc
+	switch action.Action {
+	case ActionCreate:
+	case ActionUpdate:
+		if action.ID == "" {
+			return nil, fmt.Errorf("bulk update action requires document id")
+		}
+		meta.RetryOnConflict = DefaultRetryOnConflict
+		source = upsertDoc{Doc: doc, DocAsUpsert: true}
+	case ActionIndex, "":
+		action.Action = ActionIndex
+		if action.ID != "" && action.Version > 0 {
+			meta.Version = action.Version
+			meta.VersionType = "external_gte"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bulk action: %s", action.Action)
+	}
+
+	metaData, err := json.Marshal(map[string]actionMeta{action.Action: meta})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(metaData)+len(data)+2)
+	result = append(result, metaData...)
+	result = append(result, '\n')
+	result = append(result, data...)
+	result = append(result, '\n')
+
+	return result, nil
+}
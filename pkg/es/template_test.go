@@ -45,3 +45,64 @@ func TestTemplate(t *testing.T) {
 	strResult := string(result)
 	fmt.Println(strResult)
 }
+
+func TestConvertAction(t *testing.T) {
+	doc := map[string]string{"reason": "BackOff"}
+
+	tests := []struct {
+		action BulkAction
+		want   string
+	}{
+		{
+			action: BulkAction{Index: "event"},
+			want:   "{\"index\":{\"_index\":\"event\"}}\n{\"reason\":\"BackOff\"}\n",
+		},
+		{
+			action: BulkAction{Action: ActionIndex, Index: "event", ID: "uid-1", Version: 1001},
+			want:   "{\"index\":{\"_index\":\"event\",\"_id\":\"uid-1\",\"version\":1001,\"version_type\":\"external_gte\"}}\n{\"reason\":\"BackOff\"}\n",
+		},
+		{
+			action: BulkAction{Action: ActionCreate, Index: "event", ID: "uid-1-1001"},
+			want:   "{\"create\":{\"_index\":\"event\",\"_id\":\"uid-1-1001\"}}\n{\"reason\":\"BackOff\"}\n",
+		},
+		{
+			action: BulkAction{Action: ActionUpdate, Index: "event", ID: "uid-1"},
+			want:   "{\"update\":{\"_index\":\"event\",\"_id\":\"uid-1\",\"retry_on_conflict\":3}}\n{\"doc\":{\"reason\":\"BackOff\"},\"doc_as_upsert\":true}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := ConvertAction(tt.action, doc)
+		if err != nil {
+			t.Errorf("ConvertAction(%+v) error = %v, want %v", tt.action, err, nil)
+			continue
+		}
+		if string(result) != tt.want {
+			t.Errorf("ConvertAction(%+v) = %q, want %q", tt.action, result, tt.want)
+		}
+	}
+
+	if _, err := ConvertAction(BulkAction{Action: ActionUpdate, Index: "event"}, doc); err == nil {
+		t.Errorf("ConvertAction() update without id error = %v, want error", err)
+	}
+}
+
+func TestCheckAction(t *testing.T) {
+	tests := []struct {
+		action      string
+		versionedID bool
+		wantErr     bool
+	}{
+		{"", false, false},
+		{ActionIndex, false, false},
+		{ActionCreate, true, false},
+		{ActionUpdate, true, false},
+		{ActionUpdate, false, true}, // version 비교 없이 최신 문서를 덮어쓸 수 있음
+		{"delete", false, true},
+	}
+	for _, tt := range tests {
+		if err := CheckAction(tt.action, tt.versionedID); (err != nil) != tt.wantErr {
+			t.Errorf("CheckAction(%q, %v) = %v, wantErr %v", tt.action, tt.versionedID, err, tt.wantErr)
+		}
+	}
+}
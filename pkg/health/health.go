@@ -0,0 +1,136 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	LivenessPath  string = "/healthz"
+	ReadinessPath string = "/readyz"
+)
+
+// Checker 상태 확인 함수 (정상이면 nil)
+type Checker func() error
+
+// Status 상태 정보 함수 (확인 결과에 영향을 주지 않고 응답에만 표시)
+type Status func() string
+
+type Health struct {
+	mu        sync.RWMutex
+	liveness  map[string]Checker
+	readiness map[string]Checker
+	status    map[string]Status
+}
+
+func NewHealth() *Health {
+	return &Health{
+		liveness:  make(map[string]Checker),
+		readiness: make(map[string]Checker),
+		status:    make(map[string]Status),
+	}
+}
+
+// AddLiveness liveness 확인 함수 등록
+// 실패하면 프로세스를 재시작해야 하는 상태 (ex. 처리 루프 종료, 멈춤)
+func (h *Health) AddLiveness(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.liveness[name] = checker
+}
+
+// AddReadiness readiness 확인 함수 등록
+// 실패하면 아직 요청(데이터)을 처리할 준비가 되지 않은 상태 (ex. 캐시 동기화 전, 외부 연결 실패)
+func (h *Health) AddReadiness(name string, checker Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.readiness[name] = checker
+}
+
+// AddStatus 응답에 표시할 상태 정보 등록 (ex. leader election 상태)
+func (h *Health) AddStatus(name string, status Status) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.status[name] = status
+}
+
+// Live 모든 liveness 확인 결과 반환
+func (h *Health) Live() error {
+	return h.check(h.liveness)
+}
+
+// Ready 모든 readiness 확인 결과 반환
+func (h *Health) Ready() error {
+	return h.check(h.readiness)
+}
+
+// LivenessHandler /healthz handler
+func (h *Health) LivenessHandler() http.Handler {
+	return h.handler(h.Live)
+}
+
+// ReadinessHandler /readyz handler
+func (h *Health) ReadinessHandler() http.Handler {
+	return h.handler(h.Ready)
+}
+
+func (h *Health) check(checkers map[string]Checker) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	names := make([]string, 0, len(checkers))
+	for name := range checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failed := make([]string, 0)
+	for _, name := range names {
+		if err := checkers[name](); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+
+	return nil
+}
+
+func (h *Health) handler(check func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err.Error())
+		} else {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, "ok")
+		}
+
+		for _, line := range h.statusLines() {
+			fmt.Fprintln(w, line)
+		}
+	})
+}
+
+// statusLines 이름 순서로 정렬한 상태 정보
+func (h *Health) statusLines() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	lines := make([]string, 0, len(h.status))
+	for name, status := range h.status {
+		lines = append(lines, fmt.Sprintf("%s: %s", name, status()))
+	}
+	sort.Strings(lines)
+
+	return lines
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	h := NewHealth()
+	h.AddLiveness("buffer", func() error { return nil })
+
+	ready := false
+	h.AddReadiness("kafka", func() error { return nil })
+	h.AddReadiness("elasticsearch", func() error {
+		if !ready {
+			return fmt.Errorf("ping failed")
+		}
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("liveness code = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("readiness code = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if !strings.Contains(rec.Body.String(), "elasticsearch: ping failed") {
+		t.Errorf("readiness body = %q", rec.Body.String())
+	}
+
+	ready = true
+	h.AddStatus("leader", func() string { return "client-0" })
+	rec = httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("readiness code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.String() != "ok\nleader: client-0\n" {
+		t.Errorf("readiness body = %q", rec.Body.String())
+	}
+}
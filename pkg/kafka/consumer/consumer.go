@@ -2,24 +2,38 @@ package consumer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
+	"time"
 
+	"example.com/stradvision-project/pkg/kafka/security"
 	"github.com/IBM/sarama"
 )
 
+// ErrAckTimeout ack되지 않은 메시지 때문에 partition의 offset을 commit할 수 없는 경우
+// 재시작하면 commit된 offset부터 다시 수신하므로 Run이 에러를 반환하여 프로세스를 종료하도록 함
+var ErrAckTimeout = errors.New("kafka message ack timeout")
+
 type KafkaConsumer struct {
 	cg    sarama.ConsumerGroup
 	topic string
 
-	handler sarama.ConsumerGroupHandler
+	handler *consumerGroupHandler
 	ctx     context.Context
 	cancel  context.CancelFunc
+	running atomic.Bool // Run 루프 실행 여부
+	retry   retryPolicy
+	stalled atomic.Pointer[error] // ack timeout 에러 (Run이 반환)
 
 	errFunc func(topic, msg string)
 }
 
 func NewKafkaConsumer(brokers []string, groupID, topic string, opts ...Option) (*KafkaConsumer, error) {
 	cConfig := fromOptions(opts)
+	if err := security.Apply(cConfig.config, cConfig.tls, cConfig.sasl); err != nil {
+		return nil, fmt.Errorf("failed to configure security: %w", err)
+	}
 
 	consumerGroup, err := sarama.NewConsumerGroup(brokers, groupID, cConfig.config)
 	if err != nil {
@@ -29,25 +43,93 @@ func NewKafkaConsumer(brokers []string, groupID, topic string, opts ...Option) (
 	kc := &KafkaConsumer{
 		cg:    consumerGroup,
 		topic: topic,
-		handler: consumerGroupHandler{
-			doFunc: cConfig.doFunc,
+		handler: &consumerGroupHandler{
+			doFunc:         cConfig.doFunc,
+			commitInterval: cConfig.commitInterval,
+			ackTimeout:     cConfig.ackTimeout,
 		},
 		errFunc: cConfig.errFunc,
+		retry:   cConfig.retry,
 	}
 	kc.ctx, kc.cancel = context.WithCancel(context.Background())
+	kc.handler.stallFunc = kc.stall
 
 	return kc, nil
 }
 
-func (kc *KafkaConsumer) Run() {
+// Run consumer group에 참여하여 메시지 수신
+// 일시적인 에러는 backoff 후 다시 참여하고, 연속 실패가 최대 재시도 횟수를 넘으면 에러를 반환
+// ack되지 않은 메시지가 ackTimeout을 넘으면 ErrAckTimeout을 반환
+// Close로 종료되면 nil을 반환
+func (kc *KafkaConsumer) Run() error {
+	kc.running.Store(true)
+	defer kc.running.Store(false)
+
+	go kc.drainErrors()
+
+	failures := 0
 	for {
-		if err := kc.cg.Consume(kc.ctx, []string{kc.topic}, kc.handler); err != nil {
-			kc.errFunc(kc.topic, fmt.Errorf("failed to consume: %w", err).Error())
-			return
+		err := kc.cg.Consume(kc.ctx, []string{kc.topic}, kc.handler)
+		if stalled := kc.stalled.Load(); stalled != nil {
+			return *stalled
+		}
+		if kc.ctx.Err() != nil || errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return nil
+		}
+
+		if err == nil {
+			// rebalance 등으로 session이 정상 종료되면 다시 참여
+			failures = 0
+			continue
+		}
+
+		failures++
+		kc.errFunc(kc.topic, fmt.Errorf("failed to consume (%d/%d): %w", failures, kc.retry.maxAttempts, err).Error())
+		if !isRetryableError(err) || failures >= kc.retry.maxAttempts {
+			return fmt.Errorf("failed to consume %s: %w", kc.topic, err)
+		}
+
+		select {
+		case <-kc.ctx.Done():
+			return nil
+		case <-time.After(kc.retry.backoff(failures)):
 		}
 	}
 }
 
+// stall ack timeout 에러를 기록하고 session 종료
+func (kc *KafkaConsumer) stall(err error) {
+	if kc.stalled.CompareAndSwap(nil, &err) {
+		kc.errFunc(kc.topic, err.Error())
+		kc.cancel()
+	}
+}
+
+// drainErrors consumer group에서 발생한 비동기 에러를 errFunc로 전달
+// 읽지 않으면 에러 채널이 가득 차서 consumer가 멈출 수 있음
+func (kc *KafkaConsumer) drainErrors() {
+	for err := range kc.cg.Errors() {
+		kc.errFunc(kc.topic, err.Error())
+	}
+}
+
+// IsRunning Run 루프 실행 여부
+func (kc *KafkaConsumer) IsRunning() bool {
+	return kc.running.Load()
+}
+
+// IsActive consumer group session에 참여하여 partition을 할당받았는지 여부
+func (kc *KafkaConsumer) IsActive() bool {
+	return kc.handler.active.Load()
+}
+
+// Pause 모든 partition의 메시지 수신을 일시 중지
+// 종료 시 처리 중인 메시지의 ack와 offset commit은 session이 끝날 때까지 계속 동작
+func (kc *KafkaConsumer) Pause() {
+	kc.cg.PauseAll()
+}
+
+// Close consumer group session을 종료하고 ack된 offset을 commit
 func (kc *KafkaConsumer) Close() {
 	kc.cancel()
 	kc.cg.Close()
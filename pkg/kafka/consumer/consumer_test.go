@@ -0,0 +1,101 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type testConsumerGroup struct {
+	sarama.ConsumerGroup
+
+	errs     chan error
+	consume  func(ctx context.Context) error
+	consumed int
+}
+
+func (g *testConsumerGroup) Consume(ctx context.Context, _ []string, _ sarama.ConsumerGroupHandler) error {
+	g.consumed++
+	return g.consume(ctx)
+}
+
+func (g *testConsumerGroup) Errors() <-chan error {
+	return g.errs
+}
+
+func newTestConsumer(consume func(ctx context.Context) error) (*KafkaConsumer, *testConsumerGroup) {
+	cg := &testConsumerGroup{errs: make(chan error), consume: consume}
+	kc := &KafkaConsumer{
+		cg:      cg,
+		topic:   "event",
+		handler: &consumerGroupHandler{},
+		errFunc: func(topic, msg string) {},
+		retry:   retryPolicy{maxAttempts: 3, baseBackoff: time.Millisecond, maxBackoff: time.Millisecond},
+	}
+	kc.ctx, kc.cancel = context.WithCancel(context.Background())
+
+	return kc, cg
+}
+
+func TestKafkaConsumerRun(t *testing.T) {
+	// 연속 실패가 최대 재시도 횟수를 넘으면 에러 반환
+	kc, cg := newTestConsumer(func(_ context.Context) error {
+		return sarama.ErrOutOfBrokers
+	})
+	if err := kc.Run(); !errors.Is(err, sarama.ErrOutOfBrokers) {
+		t.Errorf("Run() = %v, want %v", err, sarama.ErrOutOfBrokers)
+	}
+	if cg.consumed != 3 {
+		t.Errorf("consumed = %d, want %d", cg.consumed, 3)
+	}
+
+	// 설정 오류는 재시도하지 않음
+	kc, cg = newTestConsumer(func(_ context.Context) error {
+		return sarama.ConfigurationError("invalid")
+	})
+	if err := kc.Run(); err == nil {
+		t.Error("Run() = nil, want error")
+	}
+	if cg.consumed != 1 {
+		t.Errorf("consumed = %d, want %d", cg.consumed, 1)
+	}
+
+	// context가 취소되면 nil 반환
+	kc, cg = newTestConsumer(func(ctx context.Context) error {
+		if cg.consumed == 5 {
+			kc.cancel()
+		}
+		if cg.consumed%2 == 0 {
+			return sarama.ErrOutOfBrokers
+		}
+		return ctx.Err()
+	})
+	if err := kc.Run(); err != nil {
+		t.Errorf("Run() = %v, want nil", err)
+	}
+	if kc.IsRunning() {
+		t.Error("IsRunning() = true, want false")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := retryPolicy{baseBackoff: time.Second, maxBackoff: 5 * time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
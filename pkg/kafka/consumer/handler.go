@@ -1,17 +1,156 @@
 package consumer
 
-import "github.com/IBM/sarama"
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"example.com/stradvision-project/pkg/metrics"
+	"github.com/IBM/sarama"
+)
 
 type consumerGroupHandler struct {
-	doFunc func([]byte)
+	doFunc         func(data []byte, ack func())
+	commitInterval time.Duration
+	ackTimeout     time.Duration
+	stallFunc      func(err error) // ack되지 않은 메시지가 ackTimeout을 넘은 경우 호출
+
+	active atomic.Bool // consumer group session 참여 여부
 }
 
-func (consumerGroupHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (consumerGroupHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
-func (h consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-	for msg := range claim.Messages() {
-		h.doFunc(msg.Value)
-		session.MarkMessage(msg, "")
-	}
+func (h *consumerGroupHandler) Setup(_ sarama.ConsumerGroupSession) error {
+	h.active.Store(true)
+	return nil
+}
+
+func (h *consumerGroupHandler) Cleanup(_ sarama.ConsumerGroupSession) error {
+	h.active.Store(false)
 	return nil
 }
+
+// ConsumeClaim 메시지를 doFunc로 전달하고, ack가 호출된 메시지까지만 offset을 commit
+// 가장 오래된 메시지가 ackTimeout 동안 ack되지 않으면 이후 offset을 commit할 수 없으므로 stallFunc 호출
+func (h *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	tracker := newOffsetTracker(session, claim.Topic(), claim.Partition())
+	consumed := metrics.KafkaConsumed.WithLabelValues(claim.Topic(), strconv.Itoa(int(claim.Partition())))
+
+	ticker := time.NewTicker(h.commitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				tracker.commit()
+				return nil
+			}
+			consumed.Inc()
+			h.doFunc(msg.Value, tracker.add(msg.Offset))
+		case <-ticker.C:
+			tracker.commit()
+			if wait := tracker.oldest(); wait > h.ackTimeout {
+				h.stallFunc(fmt.Errorf("%w: %s/%d offset %d waiting %s",
+					ErrAckTimeout, claim.Topic(), claim.Partition(), tracker.head(), wait.Round(time.Second)))
+				return nil
+			}
+		case <-session.Context().Done():
+			tracker.commit()
+			return nil
+		}
+	}
+}
+
+// offsetTracker partition별로 ack된 offset을 추적
+// ack는 순서와 상관없이 호출될 수 있으므로, 앞선 메시지가 모두 ack된 offset까지만 mark
+type offsetTracker struct {
+	mu        sync.Mutex
+	session   sarama.ConsumerGroupSession
+	topic     string
+	partition int32
+
+	pending []int64        // 수신 순서대로 ack 대기 중인 offset
+	acked   map[int64]bool // ack 되었지만 앞선 offset이 남아있는 offset
+	since   time.Time      // pending[0]이 가장 오래된 ack 대기 offset이 된 시각
+	dirty   bool           // commit 되지 않은 mark가 있는지 여부
+}
+
+func newOffsetTracker(session sarama.ConsumerGroupSession, topic string, partition int32) *offsetTracker {
+	return &offsetTracker{
+		session:   session,
+		topic:     topic,
+		partition: partition,
+		pending:   make([]int64, 0),
+		acked:     make(map[int64]bool),
+	}
+}
+
+// add offset을 ack 대기 목록에 추가하고 ack 함수를 반환
+func (t *offsetTracker) add(offset int64) func() {
+	t.mu.Lock()
+	if len(t.pending) == 0 {
+		t.since = time.Now()
+	}
+	t.pending = append(t.pending, offset)
+	t.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { t.ack(offset) })
+	}
+}
+
+func (t *offsetTracker) ack(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.acked[offset] = true
+
+	next := int64(-1)
+	for len(t.pending) > 0 && t.acked[t.pending[0]] {
+		delete(t.acked, t.pending[0])
+		next = t.pending[0] + 1
+		t.pending = t.pending[1:]
+	}
+
+	if next >= 0 {
+		t.session.MarkOffset(t.topic, t.partition, next, "")
+		t.dirty = true
+		t.since = time.Now()
+	}
+}
+
+// oldest 가장 오래된 offset의 ack 대기 시간 (대기 중인 offset이 없으면 0)
+func (t *offsetTracker) oldest() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.pending) == 0 {
+		return 0
+	}
+	return time.Since(t.since)
+}
+
+// head 가장 오래된 ack 대기 offset (없으면 -1)
+func (t *offsetTracker) head() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.pending) == 0 {
+		return -1
+	}
+	return t.pending[0]
+}
+
+// commit mark된 offset이 있으면 commit
+func (t *offsetTracker) commit() {
+	t.mu.Lock()
+	dirty := t.dirty
+	t.dirty = false
+	t.mu.Unlock()
+
+	if dirty {
+		t.session.Commit()
+	}
+}
@@ -0,0 +1,75 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+type testSession struct {
+	sarama.ConsumerGroupSession
+
+	marked    []int64
+	committed int
+}
+
+func (s *testSession) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	s.marked = append(s.marked, offset)
+}
+
+func (s *testSession) Commit() {
+	s.committed++
+}
+
+func TestOffsetTracker(t *testing.T) {
+	session := &testSession{}
+	tracker := newOffsetTracker(session, "event", 0)
+
+	ack10 := tracker.add(10)
+	ack11 := tracker.add(11)
+	ack12 := tracker.add(12)
+
+	// 앞선 offset이 ack되지 않으면 mark하지 않음
+	ack11()
+	ack12()
+	if len(session.marked) != 0 {
+		t.Fatalf("marked = %v, want none", session.marked)
+	}
+
+	// 10이 ack되면 12까지 한번에 mark (다음에 읽을 offset 13)
+	ack10()
+	ack10()
+	if len(session.marked) != 1 || session.marked[0] != 13 {
+		t.Fatalf("marked = %v, want %v", session.marked, []int64{13})
+	}
+
+	tracker.commit()
+	tracker.commit()
+	if session.committed != 1 {
+		t.Errorf("committed = %d, want %d", session.committed, 1)
+	}
+}
+
+func TestOffsetTrackerOldest(t *testing.T) {
+	tracker := newOffsetTracker(&testSession{}, "event", 0)
+	if wait := tracker.oldest(); wait != 0 {
+		t.Fatalf("oldest() = %v, want 0", wait)
+	}
+
+	ack10 := tracker.add(10)
+	tracker.add(11)
+	tracker.since = tracker.since.Add(-time.Minute)
+	if wait := tracker.oldest(); wait < time.Minute {
+		t.Fatalf("oldest() = %v, want >= 1m", wait)
+	}
+
+	// 가장 오래된 offset이 ack되면 다음 offset부터 다시 측정
+	ack10()
+	if wait := tracker.oldest(); wait >= time.Minute {
+		t.Errorf("oldest() = %v, want < 1m", wait)
+	}
+	if head := tracker.head(); head != 11 {
+		t.Errorf("head() = %d, want 11", head)
+	}
+}
@@ -4,14 +4,20 @@ import (
 	"strings"
 	"time"
 
+	"example.com/stradvision-project/pkg/kafka/security"
 	"github.com/IBM/sarama"
 )
 
 type consumerConfig struct {
 	config *sarama.Config
+	tls    security.TLS  // 설정하지 않으면 PLAINTEXT
+	sasl   security.SASL // 설정하지 않으면 인증하지 않음
 
-	doFunc  func([]byte)
-	errFunc func(topic, msg string)
+	doFunc         func(data []byte, ack func())
+	errFunc        func(topic, msg string)
+	commitInterval time.Duration
+	ackTimeout     time.Duration
+	retry          retryPolicy
 }
 
 func defaultConfig() *consumerConfig {
@@ -35,10 +41,20 @@ func defaultConfig() *consumerConfig {
 	config.Consumer.Offsets.Initial = sarama.OffsetOldest // 가장 오래된 오프셋부터 시작
 	config.Consumer.Offsets.AutoCommit.Enable = false     // 자동 커밋 비활성화
 
+	// 에러 설정 (사용자 설정 불가능)
+	config.Consumer.Return.Errors = true // Errors 채널로 에러 전달
+
 	cConfig := &consumerConfig{
-		config:  config,
-		doFunc:  func([]byte) {},
-		errFunc: func(topic, msg string) {},
+		config:         config,
+		doFunc:         func(data []byte, ack func()) { ack() },
+		errFunc:        func(topic, msg string) {},
+		commitInterval: time.Second,     // ack된 offset commit 주기 1초
+		ackTimeout:     5 * time.Minute, // 메시지 ack 최대 대기 시간 5분
+		retry: retryPolicy{
+			maxAttempts: 10,               // Consume 연속 실패 최대 10회
+			baseBackoff: time.Second,      // 재시도 간격 1초부터 2배씩 증가
+			maxBackoff:  30 * time.Second, // 최대 재시도 간격 30초
+		},
 	}
 
 	return cConfig
@@ -55,7 +71,8 @@ func fromOptions(options []Option) *consumerConfig {
 type Option func(*consumerConfig)
 
 // WithDoFunc 메시지 처리 함수 설정
-func WithDoFunc(doFunc func([]byte)) Option {
+// 메시지 처리가 완료(저장 또는 전송 성공)되면 ack를 호출해야 해당 offset이 commit 됨
+func WithDoFunc(doFunc func(data []byte, ack func())) Option {
 	return func(c *consumerConfig) {
 		if doFunc != nil {
 			c.doFunc = doFunc
@@ -72,6 +89,53 @@ func WithErrFunc(errFunc func(topic, msg string)) Option {
 	}
 }
 
+// WithCommitInterval ack된 offset commit 주기 설정
+func WithCommitInterval(interval time.Duration) Option {
+	return func(c *consumerConfig) {
+		if interval > 0 {
+			c.commitInterval = interval
+		}
+	}
+}
+
+// WithAckTimeout 메시지 ack 최대 대기 시간 설정
+// 가장 오래된 메시지가 이 시간 동안 ack되지 않으면 Run이 ErrAckTimeout을 반환
+func WithAckTimeout(timeout time.Duration) Option {
+	return func(c *consumerConfig) {
+		if timeout > 0 {
+			c.ackTimeout = timeout
+		}
+	}
+}
+
+// WithConsumeRetryMax Consume 연속 실패 시 최대 재시도 횟수 설정
+// 최대 횟수를 넘으면 Run이 에러를 반환
+func WithConsumeRetryMax(max int) Option {
+	return func(c *consumerConfig) {
+		if max > 0 {
+			c.retry.maxAttempts = max
+		}
+	}
+}
+
+// WithConsumeRetryBackoff Consume 재시도 간격 설정 (실패할 때마다 2배씩 증가)
+func WithConsumeRetryBackoff(backoff time.Duration) Option {
+	return func(c *consumerConfig) {
+		if backoff > 0 {
+			c.retry.baseBackoff = backoff
+		}
+	}
+}
+
+// WithConsumeRetryMaxBackoff Consume 최대 재시도 간격 설정
+func WithConsumeRetryMaxBackoff(backoff time.Duration) Option {
+	return func(c *consumerConfig) {
+		if backoff > 0 {
+			c.retry.maxBackoff = backoff
+		}
+	}
+}
+
 // WithMinBytes 최소 메시지 크기 설정
 func WithMinBytes(min int32) Option {
 	return func(c *consumerConfig) {
@@ -180,3 +244,18 @@ func WithBalanceStrategy(strategy string) Option {
 		}
 	}
 }
+
+// WithTLS 브로커 TLS 설정 (CA, mTLS 클라이언트 인증서, server name)
+// 파일은 consumer 생성 시 읽음
+func WithTLS(tls security.TLS) Option {
+	return func(c *consumerConfig) {
+		c.tls = tls
+	}
+}
+
+// WithSASL 브로커 SASL 인증 설정 (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512)
+func WithSASL(sasl security.SASL) Option {
+	return func(c *consumerConfig) {
+		c.sasl = sasl
+	}
+}
@@ -0,0 +1,29 @@
+package consumer
+
+import (
+	"errors"
+	"time"
+
+	"example.com/stradvision-project/pkg/backoff"
+	"github.com/IBM/sarama"
+)
+
+// retryPolicy Consume 실패 시 재시도 정책
+type retryPolicy struct {
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// backoff attempt번째 연속 실패 후 대기할 시간
+// baseBackoff부터 2배씩 증가하며 maxBackoff를 넘지 않음
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	return backoff.Exponential(attempt, p.baseBackoff, p.maxBackoff, 0)
+}
+
+// isRetryableError 다시 참여하면 복구될 수 있는 에러인지 확인
+// 설정 오류는 재시도해도 복구되지 않으므로 제외
+func isRetryableError(err error) bool {
+	var configErr sarama.ConfigurationError
+	return !errors.As(err, &configErr)
+}
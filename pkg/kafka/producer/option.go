@@ -3,14 +3,24 @@ package producer
 import (
 	"time"
 
+	"example.com/stradvision-project/pkg/kafka/security"
 	"github.com/IBM/sarama"
 )
 
+const (
+	// 파티션 선택 방식 (WithPartitioner)
+	PartitionerRandom     int = 0 // 메시지마다 랜덤 파티션
+	PartitionerRoundRobin int = 1 // 파티션을 순서대로 사용
+	PartitionerHash       int = 2 // 같은 key는 항상 같은 파티션 (key 단위 순서 보장, key가 없으면 랜덤)
+)
+
 type producerConfig struct {
 	config *sarama.Config
+	tls    security.TLS  // 설정하지 않으면 PLAINTEXT
+	sasl   security.SASL // 설정하지 않으면 인증하지 않음
 
-	errFunc     func(ts time.Time, topic string, partition int32, err error)
-	successFunc func(ts time.Time, topic string, partition int32)
+	errFunc     func(result *Result)
+	successFunc func(result *Result)
 }
 
 func defaultConfig() *producerConfig {
@@ -43,8 +53,8 @@ func defaultConfig() *producerConfig {
 
 	pConfig := &producerConfig{
 		config:      config,
-		errFunc:     func(ts time.Time, topic string, partition int32, err error) {},
-		successFunc: func(ts time.Time, topic string, partition int32) {},
+		errFunc:     func(result *Result) {},
+		successFunc: func(result *Result) {},
 	}
 
 	return pConfig
@@ -62,7 +72,8 @@ func fromOptions(options []Option) *producerConfig {
 type Option func(*producerConfig)
 
 // WithErrorFunc 에러 콜백 함수 설정
-func WithErrorFunc(errFunc func(ts time.Time, topic string, partition int32, err error)) Option {
+// result에는 전송한 메시지(key, header, metadata)와 에러가 포함됨
+func WithErrorFunc(errFunc func(result *Result)) Option {
 	return func(pConfig *producerConfig) {
 		if errFunc != nil {
 			pConfig.errFunc = errFunc
@@ -71,7 +82,8 @@ func WithErrorFunc(errFunc func(ts time.Time, topic string, partition int32, err
 }
 
 // WithSuccessFunc 성공 콜백 함수 설정
-func WithSuccessFunc(successFunc func(ts time.Time, topic string, partition int32)) Option {
+// result에는 전송한 메시지(key, header, metadata)와 partition, offset이 포함됨
+func WithSuccessFunc(successFunc func(result *Result)) Option {
 	return func(pConfig *producerConfig) {
 		if successFunc != nil {
 			pConfig.successFunc = successFunc
@@ -134,16 +146,16 @@ func WithRetryBackoff(backoff time.Duration) Option {
 }
 
 // WithPartitioner 파티션 선택 설정
-// partitioner: 0(Random), 1(RoundRobin), 2(Hash)
+// partitioner: PartitionerRandom, PartitionerRoundRobin, PartitionerHash
 // default: Random
 func WithPartitioner(partitioner int) Option {
 	return func(pConfig *producerConfig) {
 		switch partitioner {
-		case 0:
+		case PartitionerRandom:
 			pConfig.config.Producer.Partitioner = sarama.NewRandomPartitioner
-		case 1:
+		case PartitionerRoundRobin:
 			pConfig.config.Producer.Partitioner = sarama.NewRoundRobinPartitioner
-		case 2:
+		case PartitionerHash:
 			pConfig.config.Producer.Partitioner = sarama.NewHashPartitioner
 		default:
 			pConfig.config.Producer.Partitioner = sarama.NewRandomPartitioner
@@ -213,3 +225,18 @@ func WithFlushMaxMessages(max int) Option {
 		}
 	}
 }
+
+// WithTLS 브로커 TLS 설정 (CA, mTLS 클라이언트 인증서, server name)
+// 파일은 producer 생성 시 읽음
+func WithTLS(tls security.TLS) Option {
+	return func(pConfig *producerConfig) {
+		pConfig.tls = tls
+	}
+}
+
+// WithSASL 브로커 SASL 인증 설정 (PLAIN, SCRAM-SHA-256, SCRAM-SHA-512)
+func WithSASL(sasl security.SASL) Option {
+	return func(pConfig *producerConfig) {
+		pConfig.sasl = sasl
+	}
+}
@@ -1,19 +1,34 @@
 package producer
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
+	"example.com/stradvision-project/pkg/kafka/security"
+	"example.com/stradvision-project/pkg/metrics"
 	"github.com/IBM/sarama"
 )
 
+// ErrProducerClosed 종료된 producer로 메시지를 전송한 경우의 에러
+var ErrProducerClosed = errors.New("kafka producer is closed")
+
 type KafkaProducer struct {
+	client   sarama.Client
 	producer sarama.AsyncProducer
 	topic    string
-	closeCh  chan struct{}
+	doneCh   chan struct{} // Run이 남은 전송 결과를 모두 처리하면 닫힘
+	runOnce  sync.Once
+
+	mu     sync.RWMutex
+	closed bool
 
-	errFunc     func(ts time.Time, topic string, partition int32, err error)
-	successFunc func(ts time.Time, topic string, partition int32)
+	errFunc     func(result *Result)
+	successFunc func(result *Result)
 }
 
 // NewKafkaProducer KafkaProducer 생성
@@ -22,16 +37,26 @@ type KafkaProducer struct {
 // opt: producer 설정
 func NewKafkaProducer(brokers []string, topic string, opts ...Option) (*KafkaProducer, error) {
 	pConfig := fromOptions(opts)
+	if err := security.Apply(pConfig.config, pConfig.tls, pConfig.sasl); err != nil {
+		return nil, fmt.Errorf("failed to configure security: %w", err)
+	}
 
-	producer, err := sarama.NewAsyncProducer(brokers, pConfig.config)
+	client, err := sarama.NewClient(brokers, pConfig.config)
 	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	producer, err := sarama.NewAsyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
 		return nil, fmt.Errorf("failed to create producer: %w", err)
 	}
 
 	kp := &KafkaProducer{
+		client:      client,
 		producer:    producer,
 		topic:       topic,
-		closeCh:     make(chan struct{}),
+		doneCh:      make(chan struct{}),
 		errFunc:     pConfig.errFunc,
 		successFunc: pConfig.successFunc,
 	}
@@ -39,32 +64,246 @@ func NewKafkaProducer(brokers []string, topic string, opts ...Option) (*KafkaPro
 	return kp, nil
 }
 
-// run KafkaProducer 결과 처리
+// Run KafkaProducer 결과 처리
+// Close 이후에도 전송 중인 메시지의 결과를 모두 처리한 뒤 종료
+// 여러 번 호출해도 결과 처리는 한 번만 실행
 func (kp *KafkaProducer) Run() {
-	for {
+	kp.runOnce.Do(kp.handleResults)
+}
+
+func (kp *KafkaProducer) handleResults() {
+	defer close(kp.doneCh)
+
+	errs, successes := kp.producer.Errors(), kp.producer.Successes()
+	for errs != nil || successes != nil {
 		select {
-		case <-kp.closeCh:
-			return
-		case err := <-kp.producer.Errors():
-			kp.errFunc(err.Msg.Timestamp, err.Msg.Topic, err.Msg.Partition, err.Err)
-		case success := <-kp.producer.Successes():
-			kp.successFunc(success.Timestamp, success.Topic, success.Partition)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			result := newResult(err.Msg, err.Err)
+			metrics.KafkaProduceFailed.WithLabelValues(result.Topic, strconv.Itoa(int(result.Partition))).Inc()
+			kp.errFunc(result)
+			deliver(err.Msg, result)
+		case success, ok := <-successes:
+			if !ok {
+				successes = nil
+				continue
+			}
+			result := newResult(success, nil)
+			metrics.KafkaProduced.WithLabelValues(result.Topic, strconv.Itoa(int(result.Partition))).Inc()
+			kp.successFunc(result)
+			if result.Message.Ack != nil {
+				result.Message.Ack()
+			}
+			deliver(success, result)
 		}
 	}
 }
 
+// Message kafka로 전송할 메시지
+type Message struct {
+	Key       string // 없으면 key 없이 전송 (partitioner가 파티션 선택)
+	Value     []byte
+	Headers   map[string]string // consumer가 payload를 해석하지 않고 분류할 수 있는 정보
+	Timestamp time.Time         // 없으면 전송 시각
+	Ack       func()            // 브로커로부터 전송 성공 응답을 받으면 호출 (실패 시에는 호출하지 않음)
+	Metadata  interface{}       // 전송 결과(Result)로 돌려받는 호출자 값 (ex. 원본 이벤트)
+}
+
+// Result 메시지 전송 결과
+type Result struct {
+	Message   Message
+	Topic     string
+	Partition int32 // 전송하지 못하면 -1
+	Offset    int64 // 전송하지 못하면 -1
+	Timestamp time.Time
+	Err       error // 전송에 성공하면 nil
+}
+
+// delivery sarama 메시지의 Metadata로 전달하는 전송 정보
+type delivery struct {
+	message Message
+	done    chan *Result // SendSync, SendBatch에서 결과를 기다리는 경우에만 설정 (buffer 1)
+}
+
+// newResult sarama 전송 결과를 Result로 변환
+func newResult(msg *sarama.ProducerMessage, err error) *Result {
+	result := &Result{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Timestamp: msg.Timestamp,
+		Err:       err,
+	}
+	if d, ok := msg.Metadata.(*delivery); ok {
+		result.Message = d.message
+	}
+	if err != nil {
+		result.Offset = -1
+	}
+	return result
+}
+
+// deliver 결과를 기다리는 호출자에게 전달
+func deliver(msg *sarama.ProducerMessage, result *Result) {
+	if d, ok := msg.Metadata.(*delivery); ok && d.done != nil {
+		d.done <- result
+	}
+}
+
 // SendMessage 메시지 전송
 func (kp *KafkaProducer) SendMessage(key string, data []byte) {
+	kp.Send(Message{Key: key, Value: data})
+}
+
+// Send key, header 등을 포함한 메시지 전송
+// 결과를 기다리지 않고, 전송 결과는 WithSuccessFunc, WithErrorFunc로 설정한 콜백으로 전달
+func (kp *KafkaProducer) Send(message Message) {
+	d := &delivery{message: message}
+	if err := kp.enqueue(context.Background(), kp.producerMessage(d)); err != nil {
+		result := kp.failedResult(message, err)
+		metrics.KafkaProduceFailed.WithLabelValues(result.Topic, "-1").Inc()
+		kp.errFunc(result)
+	}
+}
+
+// SendSync 메시지를 전송하고 브로커의 응답을 받을 때까지 대기
+// ctx가 취소되면 기다리지 않고 ctx 에러를 반환 (이미 전달한 메시지는 전송될 수 있음)
+func (kp *KafkaProducer) SendSync(ctx context.Context, message Message) (partition int32, offset int64, err error) {
+	d := &delivery{message: message, done: make(chan *Result, 1)}
+	if err := kp.enqueue(ctx, kp.producerMessage(d)); err != nil {
+		return -1, -1, err
+	}
+
+	select {
+	case result := <-d.done:
+		return result.Partition, result.Offset, result.Err
+	case <-ctx.Done():
+		return -1, -1, ctx.Err()
+	}
+}
+
+// SendBatch 메시지를 모두 전송하고 메시지별 결과를 순서대로 반환
+// ctx가 취소되면 결과를 받지 못한 메시지는 ctx 에러로 반환
+func (kp *KafkaProducer) SendBatch(ctx context.Context, messages []Message) []Result {
+	results := make([]Result, len(messages))
+	deliveries := make([]*delivery, len(messages))
+	for i, message := range messages {
+		d := &delivery{message: message, done: make(chan *Result, 1)}
+		if err := kp.enqueue(ctx, kp.producerMessage(d)); err != nil {
+			results[i] = *kp.failedResult(message, err)
+			continue
+		}
+		deliveries[i] = d
+	}
+
+	for i, d := range deliveries {
+		if d == nil {
+			continue
+		}
+		select {
+		case result := <-d.done:
+			results[i] = *result
+		case <-ctx.Done():
+			results[i] = *kp.failedResult(d.message, ctx.Err())
+		}
+	}
+	return results
+}
+
+// producerMessage sarama 메시지 생성
+func (kp *KafkaProducer) producerMessage(d *delivery) *sarama.ProducerMessage {
 	msg := &sarama.ProducerMessage{
-		Topic: kp.topic,
-		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(data),
+		Topic:     kp.topic,
+		Value:     sarama.ByteEncoder(d.message.Value),
+		Headers:   recordHeaders(d.message.Headers),
+		Timestamp: d.message.Timestamp,
+		Metadata:  d,
+	}
+	if d.message.Key != "" {
+		msg.Key = sarama.StringEncoder(d.message.Key)
+	}
+	return msg
+}
+
+// failedResult producer에 전달하지 못한 메시지의 결과
+func (kp *KafkaProducer) failedResult(message Message, err error) *Result {
+	return &Result{
+		Message:   message,
+		Topic:     kp.topic,
+		Partition: -1,
+		Offset:    -1,
+		Timestamp: time.Now(),
+		Err:       err,
+	}
+}
+
+// recordHeaders header map을 kafka record header로 변환 (key 순서로 정렬)
+func recordHeaders(headers map[string]string) []sarama.RecordHeader {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]sarama.RecordHeader, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, sarama.RecordHeader{Key: []byte(key), Value: []byte(headers[key])})
+	}
+	return result
+}
+
+// enqueue 메시지를 producer에 전달
+// 종료된 producer에 전달하면 panic이 발생하므로 ErrProducerClosed 반환
+func (kp *KafkaProducer) enqueue(ctx context.Context, msg *sarama.ProducerMessage) error {
+	kp.mu.RLock()
+	defer kp.mu.RUnlock()
+
+	if kp.closed {
+		return ErrProducerClosed
+	}
+
+	select {
+	case kp.producer.Input() <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	kp.producer.Input() <- msg
+}
+
+// Ping 브로커에서 topic metadata를 조회하여 연결 상태 확인
+func (kp *KafkaProducer) Ping() error {
+	if kp.client.Closed() {
+		return fmt.Errorf("kafka client is closed")
+	}
+
+	if err := kp.client.RefreshMetadata(kp.topic); err != nil {
+		return fmt.Errorf("failed to refresh metadata: %w", err)
+	}
+
+	return nil
 }
 
 // Close KafkaProducer 종료
+// 새로운 메시지를 받지 않고, 전송 중인 메시지의 결과(ack)를 모두 처리할 때까지 대기
+// Run을 실행하지 않은 producer는 Close에서 결과를 처리하므로 대기하지 않고 종료
 func (kp *KafkaProducer) Close() {
-	close(kp.closeCh)
+	kp.mu.Lock()
+	if kp.closed {
+		kp.mu.Unlock()
+		return
+	}
+	kp.closed = true
+	kp.mu.Unlock()
+
 	kp.producer.AsyncClose()
+	go kp.Run()
+	<-kp.doneCh
+	_ = kp.client.Close()
 }
@@ -0,0 +1,136 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// testAsyncProducer key가 "fail"인 메시지는 실패, 그 외는 성공으로 응답하는 producer
+type testAsyncProducer struct {
+	sarama.AsyncProducer
+
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errs      chan *sarama.ProducerError
+}
+
+func newTestAsyncProducer() *testAsyncProducer {
+	p := &testAsyncProducer{
+		input:     make(chan *sarama.ProducerMessage),
+		successes: make(chan *sarama.ProducerMessage),
+		errs:      make(chan *sarama.ProducerError),
+	}
+	go func() {
+		defer close(p.successes)
+		defer close(p.errs)
+
+		var offset int64
+		for msg := range p.input {
+			if key, _ := msg.Key.Encode(); string(key) == "fail" {
+				p.errs <- &sarama.ProducerError{Msg: msg, Err: errors.New("broker error")}
+				continue
+			}
+			offset++
+			msg.Partition, msg.Offset = 1, offset
+			p.successes <- msg
+		}
+	}()
+	return p
+}
+
+func (p *testAsyncProducer) Input() chan<- *sarama.ProducerMessage     { return p.input }
+func (p *testAsyncProducer) Successes() <-chan *sarama.ProducerMessage { return p.successes }
+func (p *testAsyncProducer) Errors() <-chan *sarama.ProducerError      { return p.errs }
+func (p *testAsyncProducer) AsyncClose()                               { close(p.input) }
+
+func TestSendBatch(t *testing.T) {
+	var failed []*Result
+	kp := &KafkaProducer{
+		producer:    newTestAsyncProducer(),
+		topic:       "dlq",
+		doneCh:      make(chan struct{}),
+		errFunc:     func(result *Result) { failed = append(failed, result) },
+		successFunc: func(result *Result) {},
+	}
+	go kp.Run()
+	defer func() {
+		kp.producer.AsyncClose()
+		<-kp.doneCh
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	acked := 0
+	results := kp.SendBatch(ctx, []Message{
+		{Key: "a", Value: []byte("1"), Metadata: "event-1", Ack: func() { acked++ }},
+		{Key: "fail", Value: []byte("2"), Metadata: "event-2", Ack: func() { acked++ }},
+		{Key: "b", Value: []byte("3"), Metadata: "event-3", Headers: map[string]string{"kind": "Pod"}},
+	})
+	if len(results) != 3 {
+		t.Fatalf("results = %d, want 3", len(results))
+	}
+	for i, want := range []string{"event-1", "event-2", "event-3"} {
+		if results[i].Message.Metadata != want {
+			t.Errorf("results[%d].Message.Metadata = %v, want %s", i, results[i].Message.Metadata, want)
+		}
+	}
+	if results[0].Err != nil || results[0].Partition != 1 || results[0].Offset != 1 {
+		t.Errorf("results[0] = %+v, want partition 1, offset 1", results[0])
+	}
+	if results[1].Err == nil || results[1].Offset != -1 {
+		t.Errorf("results[1] = %+v, want error", results[1])
+	}
+	if results[2].Err != nil || results[2].Message.Headers["kind"] != "Pod" {
+		t.Errorf("results[2] = %+v, want success with headers", results[2])
+	}
+	if acked != 1 {
+		t.Errorf("acked = %d, want 1", acked)
+	}
+	if len(failed) != 1 || failed[0].Message.Metadata != "event-2" {
+		t.Errorf("error callback = %+v, want event-2", failed)
+	}
+
+	partition, offset, err := kp.SendSync(ctx, Message{Key: "c", Value: []byte("4")})
+	if err != nil || partition != 1 || offset != 3 {
+		t.Errorf("SendSync() = %d, %d, %v, want 1, 3, nil", partition, offset, err)
+	}
+	if _, _, err := kp.SendSync(ctx, Message{Key: "fail"}); err == nil {
+		t.Errorf("SendSync() error = nil, want error")
+	}
+
+	kp.closed = true
+	if _, _, err := kp.SendSync(ctx, Message{Key: "d"}); !errors.Is(err, ErrProducerClosed) {
+		t.Errorf("SendSync() error = %v, want ErrProducerClosed", err)
+	}
+}
+
+type testClient struct {
+	sarama.Client
+}
+
+func (testClient) Close() error { return nil }
+
+func TestCloseWithoutRun(t *testing.T) {
+	kp := &KafkaProducer{
+		client:   testClient{},
+		producer: newTestAsyncProducer(),
+		doneCh:   make(chan struct{}),
+	}
+
+	// Run을 실행하지 않아도 Close가 대기하지 않고 종료
+	done := make(chan struct{})
+	go func() {
+		kp.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close() blocked without Run")
+	}
+}
@@ -0,0 +1,159 @@
+package security
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+const (
+	// SASL mechanism
+	MechanismPlain       string = "PLAIN"
+	MechanismSCRAMSHA256 string = "SCRAM-SHA-256"
+	MechanismSCRAMSHA512 string = "SCRAM-SHA-512"
+)
+
+// TLS kafka 브로커 TLS 설정
+// Strimzi의 cluster CA secret(ca.crt)과 KafkaUser secret(user.crt, user.key)을 파일로 mount하여 사용
+type TLS struct {
+	Enabled    bool   `yaml:"enabled" env:"ENABLED"`
+	CAFile     string `yaml:"caFile" env:"CA_FILE"`         // 없으면 시스템 CA 사용
+	CertFile   string `yaml:"certFile" env:"CERT_FILE"`     // mTLS 클라이언트 인증서 (keyFile과 함께 설정)
+	KeyFile    string `yaml:"keyFile" env:"KEY_FILE"`       // mTLS 클라이언트 키
+	ServerName string `yaml:"serverName" env:"SERVER_NAME"` // 없으면 브로커 주소의 host 사용
+}
+
+// SASL kafka 브로커 SASL 인증 설정
+// mechanism이 없으면 인증하지 않음
+type SASL struct {
+	Mechanism    string `yaml:"mechanism" env:"MECHANISM"` // PLAIN, SCRAM-SHA-256, SCRAM-SHA-512
+	Username     string `yaml:"username" env:"USERNAME"`
+	Password     string `yaml:"password" env:"PASSWORD" secret:"true"`
+	PasswordFile string `yaml:"passwordFile" env:"PASSWORD_FILE"` // Strimzi KafkaUser secret의 password 파일 (password보다 우선)
+}
+
+// Validate TLS 설정 확인
+func (t TLS) Validate() error {
+	if !t.Enabled {
+		return nil
+	}
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("tls certFile and keyFile must be set together")
+	}
+	return nil
+}
+
+// Validate SASL 설정 확인
+func (s SASL) Validate() error {
+	if s.Mechanism == "" {
+		return nil
+	}
+
+	switch strings.ToUpper(s.Mechanism) {
+	case MechanismPlain, MechanismSCRAMSHA256, MechanismSCRAMSHA512:
+	default:
+		return fmt.Errorf("sasl mechanism invalid: %s", s.Mechanism)
+	}
+	if s.Username == "" {
+		return fmt.Errorf("sasl username required")
+	}
+	if s.Password == "" && s.PasswordFile == "" {
+		return fmt.Errorf("sasl password or passwordFile required")
+	}
+	return nil
+}
+
+// Config TLS 설정으로 tls.Config 생성
+func (t TLS) Config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName: t.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if t.CAFile != "" {
+		ca, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse ca file: %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// password passwordFile이 있으면 파일에서 읽음 (마지막 개행 제거)
+func (s SASL) password() (string, error) {
+	if s.PasswordFile == "" {
+		return s.Password, nil
+	}
+
+	data, err := os.ReadFile(s.PasswordFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// Apply sarama 설정에 TLS, SASL 설정 적용
+func Apply(config *sarama.Config, tlsConfig TLS, sasl SASL) error {
+	if err := tlsConfig.Validate(); err != nil {
+		return err
+	}
+	if err := sasl.Validate(); err != nil {
+		return err
+	}
+
+	if tlsConfig.Enabled {
+		c, err := tlsConfig.Config()
+		if err != nil {
+			return err
+		}
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = c
+	}
+
+	if sasl.Mechanism == "" {
+		return nil
+	}
+	password, err := sasl.password()
+	if err != nil {
+		return err
+	}
+	config.Net.SASL.Enable = true
+	config.Net.SASL.User = sasl.Username
+	config.Net.SASL.Password = password
+
+	switch strings.ToUpper(sasl.Mechanism) {
+	case MechanismPlain:
+		config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case MechanismSCRAMSHA256:
+		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return newSCRAMClient(scram.SHA256)
+		}
+	case MechanismSCRAMSHA512:
+		config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		config.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return newSCRAMClient(scram.SHA512)
+		}
+	}
+
+	return nil
+}
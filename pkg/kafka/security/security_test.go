@@ -0,0 +1,102 @@
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+// TestSCRAMClient RFC 7677 SCRAM-SHA-256 예제
+func TestSCRAMClient(t *testing.T) {
+	c := newSCRAMClient(scram.SHA256)
+	c.newNonce = func() string { return "rOprNGfwEbeRWgbNEkqO" }
+	if err := c.Begin("user", "pencil", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	clientFirst, err := c.Step("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "n,,n=user,r=rOprNGfwEbeRWgbNEkqO"; clientFirst != want {
+		t.Errorf("client-first = %s, want %s", clientFirst, want)
+	}
+
+	clientFinal, err := c.Step("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="; clientFinal != want {
+		t.Errorf("client-final = %s, want %s", clientFinal, want)
+	}
+	if c.Done() {
+		t.Errorf("Done() = true before server-final")
+	}
+
+	if _, err := c.Step("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Done() {
+		t.Errorf("Done() = false, want true")
+	}
+
+	c.Begin("user", "pencil", "")
+	c.Step("")
+	c.Step("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+	if _, err := c.Step("v=AAAA"); err == nil {
+		t.Errorf("Step() error = nil, want server signature mismatch")
+	}
+
+	// 최소 iteration(4096)보다 작으면 인증하지 않음
+	c.Begin("user", "pencil", "")
+	c.Step("")
+	if _, err := c.Step("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=1"); err == nil {
+		t.Errorf("Step() error = nil, want iteration count error")
+	}
+}
+
+func TestApply(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := sarama.NewConfig()
+	err := Apply(config, TLS{Enabled: true, ServerName: "kafka"}, SASL{
+		Mechanism:    "scram-sha-512",
+		Username:     "client",
+		Password:     "ignored",
+		PasswordFile: passwordFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.Net.TLS.Enable || config.Net.TLS.Config.ServerName != "kafka" {
+		t.Errorf("tls = %v, %+v", config.Net.TLS.Enable, config.Net.TLS.Config)
+	}
+	if config.Net.SASL.Mechanism != sarama.SASLTypeSCRAMSHA512 || config.Net.SASL.Password != "secret" {
+		t.Errorf("sasl = %s, %s", config.Net.SASL.Mechanism, config.Net.SASL.Password)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		tls  TLS
+		sasl SASL
+	}{
+		{name: "cert without key", tls: TLS{Enabled: true, CertFile: "user.crt"}},
+		{name: "unknown mechanism", sasl: SASL{Mechanism: "GSSAPI", Username: "client", Password: "secret"}},
+		{name: "no password", sasl: SASL{Mechanism: MechanismPlain, Username: "client"}},
+		{name: "missing ca file", tls: TLS{Enabled: true, CAFile: "/nonexistent/ca.crt"}},
+	}
+	for _, tt := range tests {
+		if err := Apply(sarama.NewConfig(), tt.tls, tt.sasl); err == nil {
+			t.Errorf("%s: Apply() error = nil", tt.name)
+		}
+	}
+}
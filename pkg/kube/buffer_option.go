@@ -0,0 +1,75 @@
+package kube
+
+import "time"
+
+type bufferConfig struct {
+	flushMaxCount int
+	flushMaxBytes int
+	flushInterval time.Duration
+	queueSize     int
+	dropWhenFull  bool
+}
+
+func defaultBufferConfig() *bufferConfig {
+	return &bufferConfig{
+		flushMaxCount: DefaultFlushMaxCount,
+		flushMaxBytes: DefaultFlushMaxBytes,
+		flushInterval: DefaultFlushMaxTime,
+		queueSize:     DefaultQueueSize,
+		dropWhenFull:  false,
+	}
+}
+
+type BufferOption func(*bufferConfig)
+
+func fromBufferOptions(options []BufferOption) *bufferConfig {
+	config := defaultBufferConfig()
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithFlushMaxCount flush할 최대 이벤트 개수 설정
+func WithFlushMaxCount(count int) BufferOption {
+	return func(c *bufferConfig) {
+		if count > 0 {
+			c.flushMaxCount = count
+		}
+	}
+}
+
+// WithFlushMaxBytes flush할 최대 이벤트 크기(JSON 기준) 설정
+func WithFlushMaxBytes(bytes int) BufferOption {
+	return func(c *bufferConfig) {
+		if bytes > 0 {
+			c.flushMaxBytes = bytes
+		}
+	}
+}
+
+// WithFlushInterval 최대 flush 주기 설정
+func WithFlushInterval(interval time.Duration) BufferOption {
+	return func(c *bufferConfig) {
+		if interval > 0 {
+			c.flushInterval = interval
+		}
+	}
+}
+
+// WithQueueSize flush 대기 중인 buffer와 별도로 수신 대기열 크기 설정
+func WithQueueSize(size int) BufferOption {
+	return func(c *bufferConfig) {
+		if size >= 0 {
+			c.queueSize = size
+		}
+	}
+}
+
+// WithDropWhenFull 수신 대기열이 가득 찼을 때 이벤트를 버릴지 설정
+// false(기본값)면 대기열에 자리가 날 때까지 AddEvent가 대기
+func WithDropWhenFull(drop bool) BufferOption {
+	return func(c *bufferConfig) {
+		c.dropWhenFull = drop
+	}
+}
@@ -2,33 +2,62 @@ package kube
 
 import (
 	"fmt"
+	"sync/atomic"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 type Client struct {
-	cs       *kubernetes.Clientset
-	iFactory informers.SharedInformerFactory
-	closeCh  chan struct{}
+	cs         *kubernetes.Clientset
+	eFactories []informers.SharedInformerFactory // 이벤트 리소스별 (namespace, field selector 적용)
+	iFactory   informers.SharedInformerFactory
+	dFactory   dynamicinformer.DynamicSharedInformerFactory
+	nFactories []informers.SharedInformerFactory // 이벤트 대상 리소스 정보 (WithEnrichment, 수집 대상 namespace만 조회)
+	enricher   *Enricher
+	closeCh    chan struct{}
+	synced     atomic.Bool // informer 캐시 동기화 여부
 
-	sii cache.SharedIndexInformer              // SharedIndexInformer 객체
-	reg cache.ResourceEventHandlerRegistration // event handler 등록 정보
+	informers []resourceInformer // 리소스별 informer
+	involved  []*involvedHandler // 대상 리소스 label을 조회하는 이벤트 handler (label selector 설정 시)
+}
+
+// resourceInformer 리소스별 informer와 event handler 등록 정보
+type resourceInformer struct {
+	gvr schema.GroupVersionResource
+	sii cache.SharedIndexInformer
+	reg cache.ResourceEventHandlerRegistration
 }
 
 // NewClient kubernetes client 생성
+// WithResources로 설정한 리소스마다 informer를 생성하여 eventHandler를 등록 (기본값 events.k8s.io/v1 events)
+// 기본 리소스는 typed informer, 그 외 리소스(CRD 등)는 dynamic informer를 사용하며
+// handler에는 typed 객체 또는 *unstructured.Unstructured가 전달됨
 func NewClient(eventHandler cache.ResourceEventHandler, options ...Option) (*Client, error) {
 	config := fromOptions(options)
-	client := &Client{}
+	client := &Client{closeCh: make(chan struct{})}
 
 	// clientConfig 설정
 	var clientConfig *rest.Config
 	var err error
-	if config.kubeConfig != "" {
-		clientConfig, err = clientcmd.BuildConfigFromFlags("", config.kubeConfig)
+	if config.kubeConfig != "" || config.context != "" {
+		// kubeconfig 파일이 없으면 KUBECONFIG, ~/.kube/config 순서로 조회
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+		loadingRules.ExplicitPath = config.kubeConfig
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: config.context}
+		clientConfig, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
 		if err != nil {
 			return nil, fmt.Errorf("failed to build config from kubernetes config: %v", err)
 		}
@@ -44,28 +73,201 @@ func NewClient(eventHandler cache.ResourceEventHandler, options ...Option) (*Cli
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes clientset: %v", err)
 	}
+	dc, err := dynamic.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes dynamic client: %v", err)
+	}
+
+	// 수집 대상 필터
+	// 서버에서 필터링할 수 있는 조건은 ListOptions로, 나머지는 handler 앞에서 필터링
+	if _, err := fields.ParseSelector(config.fieldSelector); err != nil {
+		return nil, fmt.Errorf("invalid field selector: %v", err)
+	}
+	for _, gvr := range config.resources {
+		if isEventResource(gvr) {
+			if _, err := eventFieldSelector(config.fieldSelector, gvr); err != nil {
+				return nil, fmt.Errorf("invalid field selector for %s: %v", gvr.String(), err)
+			}
+		}
+	}
+	labelSelector, err := labels.Parse(config.labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %v", err)
+	}
+
+	eventNamespace := metav1.NamespaceAll
+	if len(config.namespaces) == 1 {
+		eventNamespace = config.namespaces[0]
+	}
+	excludeSelector := ""
+	for _, ns := range config.excludeNamespaces {
+		excludeSelector = joinSelectors(excludeSelector, "metadata.namespace!="+ns)
+	}
+	fieldSelector := config.fieldSelector
+	if eventNamespace == metav1.NamespaceAll {
+		fieldSelector = joinSelectors(fieldSelector, excludeSelector)
+	}
+	// 이벤트 API마다 field 이름이 다르므로(regarding.*, involvedObject.*) 리소스별로 변환
+	eventTweak := func(gvr schema.GroupVersionResource) func(*metav1.ListOptions) {
+		selector, _ := eventFieldSelector(fieldSelector, gvr)
+		return func(options *metav1.ListOptions) {
+			options.FieldSelector = selector
+		}
+	}
+	objectTweak := func(options *metav1.ListOptions) {
+		options.LabelSelector = config.labelSelector
+	}
+
+	filter := &objectFilter{include: make(map[string]bool), exclude: make(map[string]bool)}
+	for _, ns := range config.namespaces {
+		filter.include[ns] = true
+	}
+	for _, ns := range config.excludeNamespaces {
+		filter.exclude[ns] = true
+	}
+	eventFilter := *filter
+	if !labelSelector.Empty() {
+		mc, err := metadata.NewForConfig(clientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes metadata client: %v", err)
+		}
+		mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(client.cs.Discovery()))
+		eventFilter.involvedSelector = labelSelector
+		eventFilter.involved = newInvolvedLabels(mc, mapper)
+	}
 
 	// SharedInformerFactory 생성
-	client.iFactory = informers.NewSharedInformerFactory(client.cs, config.resyncTime)
+	client.iFactory = informers.NewSharedInformerFactoryWithOptions(client.cs, config.resyncTime,
+		informers.WithTweakListOptions(objectTweak),
+	)
+	client.dFactory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(dc, config.resyncTime,
+		metav1.NamespaceAll, objectTweak,
+	)
 
-	// SharedIndexInformer, ResourceEventHandler 생성
-	client.sii = client.iFactory.Events().V1().Events().Informer()
-	client.reg, err = client.sii.AddEventHandler(eventHandler)
-	if err != nil {
-		return nil, fmt.Errorf("failed to add kubernetes event handler: %v", err)
+	// 이벤트 대상 리소스 정보는 label selector 없이 수집 대상 namespace만 조회
+	// 수집할 리소스 informer보다 먼저 시작하므로 iFactory와 별도 factory 사용 (Node는 cluster 리소스이므로 namespace 제한 없음)
+	if config.enrich {
+		namespaced := make(map[string]informers.SharedInformerFactory)
+		if len(config.namespaces) > 0 {
+			for _, ns := range config.namespaces {
+				namespaced[ns] = informers.NewSharedInformerFactoryWithOptions(client.cs, config.resyncTime,
+					informers.WithNamespace(ns),
+				)
+			}
+		} else {
+			namespaced[metav1.NamespaceAll] = informers.NewSharedInformerFactoryWithOptions(client.cs, config.resyncTime,
+				informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+					options.FieldSelector = excludeSelector
+				}),
+			)
+		}
+		cluster := informers.NewSharedInformerFactory(client.cs, config.resyncTime)
+
+		for _, factory := range namespaced {
+			client.nFactories = append(client.nFactories, factory)
+		}
+		client.nFactories = append(client.nFactories, cluster)
+		client.enricher = newEnricher(namespaced, cluster, config.enrichLabels, config.enrichAnnotations)
+	}
+
+	// 리소스별 SharedIndexInformer, ResourceEventHandler 생성
+	for _, gvr := range config.resources {
+		var informer informers.GenericInformer
+		handler := filter.handler(eventHandler, config.errFunc)
+		if isEventResource(gvr) {
+			eFactory := informers.NewSharedInformerFactoryWithOptions(client.cs, config.resyncTime,
+				informers.WithNamespace(eventNamespace), informers.WithTweakListOptions(eventTweak(gvr)),
+			)
+			client.eFactories = append(client.eFactories, eFactory)
+			informer, err = eFactory.ForResource(gvr)
+			handler = eventFilter.handler(eventHandler, config.errFunc)
+			if ih, ok := handler.(*involvedHandler); ok {
+				client.involved = append(client.involved, ih)
+			}
+		} else {
+			informer, err = client.iFactory.ForResource(gvr)
+		}
+		if err != nil {
+			// typed informer가 없는 리소스는 dynamic informer 사용
+			informer = client.dFactory.ForResource(gvr)
+		}
+
+		ri := resourceInformer{gvr: gvr, sii: informer.Informer()}
+		ri.reg, err = ri.sii.AddEventHandler(handler)
+		if err != nil {
+			client.stopInvolved()
+			return nil, fmt.Errorf("failed to add kubernetes event handler for %s: %v", gvr.String(), err)
+		}
+		client.informers = append(client.informers, ri)
 	}
 
 	return client, nil
 }
 
 // Run client 실행
+// 모든 informer 캐시 동기화가 끝나면 HasSynced가 true를 반환
 func (c *Client) Run() {
-	c.closeCh = make(chan struct{})
-	go c.iFactory.Start(c.closeCh)
+	// 이벤트를 처리하기 전에 대상 리소스 정보 캐시를 먼저 동기화
+	if c.enricher != nil {
+		for _, nFactory := range c.nFactories {
+			nFactory.Start(c.closeCh)
+		}
+		if !cache.WaitForCacheSync(c.closeCh, c.enricher.synced...) {
+			return
+		}
+	}
+
+	for _, eFactory := range c.eFactories {
+		eFactory.Start(c.closeCh)
+	}
+	c.iFactory.Start(c.closeCh)
+	c.dFactory.Start(c.closeCh)
+
+	synced := make([]cache.InformerSynced, 0, len(c.informers))
+	for _, ri := range c.informers {
+		synced = append(synced, ri.sii.HasSynced)
+	}
+	if cache.WaitForCacheSync(c.closeCh, synced...) {
+		c.synced.Store(true)
+	}
+}
+
+// Enricher 이벤트 대상 리소스 정보 추가 (WithEnrichment를 설정하지 않으면 nil)
+func (c *Client) Enricher() *Enricher {
+	return c.enricher
+}
+
+// Clientset kubernetes clientset (leader election 등에서 사용)
+func (c *Client) Clientset() kubernetes.Interface {
+	return c.cs
+}
+
+// HasSynced informer 캐시 동기화 여부
+func (c *Client) HasSynced() bool {
+	return c.synced.Load()
 }
 
 // Close client 종료
+// informer가 모두 종료될 때까지 대기하므로, 반환된 후에는 이벤트 handler가 호출되지 않음
 func (c *Client) Close() {
-	_ = c.sii.RemoveEventHandler(c.reg)
+	for _, ri := range c.informers {
+		_ = ri.sii.RemoveEventHandler(ri.reg)
+	}
+	c.stopInvolved()
 	close(c.closeCh)
+	for _, eFactory := range c.eFactories {
+		eFactory.Shutdown()
+	}
+	c.iFactory.Shutdown()
+	c.dFactory.Shutdown()
+	for _, nFactory := range c.nFactories {
+		nFactory.Shutdown()
+	}
+}
+
+// stopInvolved 대상 리소스 label을 조회하는 worker 종료
+func (c *Client) stopInvolved() {
+	for _, ih := range c.involved {
+		ih.stop()
+	}
 }
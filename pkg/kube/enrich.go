@@ -0,0 +1,232 @@
+package kube
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// maxOwnerDepth owner chain 최대 길이 (순환 참조 방지)
+	maxOwnerDepth = 5
+)
+
+// Involved 이벤트 대상 리소스(regarding)의 추가 정보
+type Involved struct {
+	Owners      []Owner           `json:"owners,omitempty"` // 가까운 순서, ex) ReplicaSet, Deployment
+	NodeName    string            `json:"nodeName,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Images      []string          `json:"images,omitempty"`
+}
+
+// Owner 대상 리소스의 controller owner
+type Owner struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+}
+
+// Enricher Pod, ReplicaSet, Node informer 캐시로 이벤트 대상 리소스의 정보를 추가
+// 캐시에 없는 리소스(수집 대상이 아닌 namespace 등)나 그 외 kind의 이벤트는 변경하지 않음
+type Enricher struct {
+	pods        map[string]corelisters.PodLister // namespace별 (metav1.NamespaceAll이면 모든 namespace)
+	replicaSets map[string]appslisters.ReplicaSetLister
+	nodes       corelisters.NodeLister
+	synced      []cache.InformerSynced
+
+	labels      []string // 추가할 label key (없으면 모든 label)
+	annotations []string // 추가할 annotation key (없으면 추가하지 않음)
+}
+
+// newEnricher namespace별 factory에 Pod, ReplicaSet informer를, cluster factory에 Node informer 등록
+// namespaced: 수집 대상 namespace별 factory (key가 metav1.NamespaceAll이면 모든 namespace)
+// factory는 label selector 등 리소스 필터가 없어야 함
+func newEnricher(namespaced map[string]informers.SharedInformerFactory, cluster informers.SharedInformerFactory, labels, annotations []string) *Enricher {
+	e := &Enricher{
+		pods:        make(map[string]corelisters.PodLister),
+		replicaSets: make(map[string]appslisters.ReplicaSetLister),
+		labels:      labels,
+		annotations: annotations,
+	}
+
+	for namespace, factory := range namespaced {
+		pods := factory.Core().V1().Pods()
+		replicaSets := factory.Apps().V1().ReplicaSets()
+		e.pods[namespace] = pods.Lister()
+		e.replicaSets[namespace] = replicaSets.Lister()
+		e.synced = append(e.synced, pods.Informer().HasSynced, replicaSets.Informer().HasSynced)
+	}
+
+	nodes := cluster.Core().V1().Nodes()
+	e.nodes = nodes.Lister()
+	e.synced = append(e.synced, nodes.Informer().HasSynced)
+
+	return e
+}
+
+// podLister namespace의 Pod lister (수집 대상 namespace가 아니면 nil)
+func (e *Enricher) podLister(namespace string) corelisters.PodNamespaceLister {
+	if lister, ok := e.pods[namespace]; ok {
+		return lister.Pods(namespace)
+	}
+	if lister, ok := e.pods[metav1.NamespaceAll]; ok {
+		return lister.Pods(namespace)
+	}
+	return nil
+}
+
+// replicaSetLister namespace의 ReplicaSet lister (수집 대상 namespace가 아니면 nil)
+func (e *Enricher) replicaSetLister(namespace string) appslisters.ReplicaSetNamespaceLister {
+	if lister, ok := e.replicaSets[namespace]; ok {
+		return lister.ReplicaSets(namespace)
+	}
+	if lister, ok := e.replicaSets[metav1.NamespaceAll]; ok {
+		return lister.ReplicaSets(namespace)
+	}
+	return nil
+}
+
+// HasSynced 캐시 동기화 여부
+func (e *Enricher) HasSynced() bool {
+	for _, synced := range e.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// Enrich 이벤트 대상 리소스의 owner chain, node, label, annotation, container image 추가
+func (e *Enricher) Enrich(event *Event) {
+	regarding := event.Regarding
+
+	var object metav1.Object
+	involved := &Involved{}
+	switch regarding.Kind {
+	case "Pod":
+		lister := e.podLister(regarding.Namespace)
+		if lister == nil {
+			return
+		}
+		pod, err := lister.Get(regarding.Name)
+		if err != nil {
+			return
+		}
+		object = pod
+		involved.NodeName = pod.Spec.NodeName
+		involved.Images = images(&pod.Spec)
+	case "ReplicaSet":
+		lister := e.replicaSetLister(regarding.Namespace)
+		if lister == nil {
+			return
+		}
+		rs, err := lister.Get(regarding.Name)
+		if err != nil {
+			return
+		}
+		object = rs
+		involved.Images = images(&rs.Spec.Template.Spec)
+	case "Node":
+		node, err := e.nodes.Get(regarding.Name)
+		if err != nil {
+			return
+		}
+		object = node
+		involved.NodeName = node.Name
+	default:
+		return
+	}
+
+	// 같은 이름으로 다시 생성된 리소스는 제외
+	if regarding.UID != "" && string(object.GetUID()) != regarding.UID {
+		return
+	}
+
+	involved.Owners = e.owners(object.GetNamespace(), object.GetOwnerReferences())
+	involved.Labels = selectKeys(object.GetLabels(), e.labels, true)
+	involved.Annotations = selectKeys(object.GetAnnotations(), e.annotations, false)
+	event.Involved = involved
+}
+
+// owners controller owner를 따라가며 owner chain 반환 (Pod→ReplicaSet→Deployment)
+// 캐시에 있는 ReplicaSet만 따라가고, 그 외 owner(StatefulSet, Job 등)에서 멈춤
+func (e *Enricher) owners(namespace string, refs []metav1.OwnerReference) []Owner {
+	owners := make([]Owner, 0)
+	lister := e.replicaSetLister(namespace)
+	for len(owners) < maxOwnerDepth {
+		ref := controllerOf(refs)
+		if ref == nil {
+			break
+		}
+		owners = append(owners, Owner{Kind: ref.Kind, Name: ref.Name, UID: string(ref.UID)})
+		if ref.Kind != "ReplicaSet" || lister == nil {
+			break
+		}
+
+		rs, err := lister.Get(ref.Name)
+		if err != nil || rs.UID != ref.UID {
+			break
+		}
+		refs = rs.OwnerReferences
+	}
+
+	if len(owners) == 0 {
+		return nil
+	}
+	return owners
+}
+
+// controllerOf controller owner 반환 (없으면 nil)
+func controllerOf(refs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range refs {
+		if refs[i].Controller != nil && *refs[i].Controller {
+			return &refs[i]
+		}
+	}
+	return nil
+}
+
+// images init container와 container의 image 목록
+func images(spec *corev1.PodSpec) []string {
+	result := make([]string, 0, len(spec.InitContainers)+len(spec.Containers))
+	for _, c := range spec.InitContainers {
+		result = append(result, c.Image)
+	}
+	for _, c := range spec.Containers {
+		result = append(result, c.Image)
+	}
+	return result
+}
+
+// selectKeys keys에 해당하는 값만 복사하여 반환
+// keys가 없으면 all에 따라 모든 값 또는 nil 반환
+func selectKeys(values map[string]string, keys []string, all bool) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(keys) == 0 {
+		if !all {
+			return nil
+		}
+		keys = make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+
+	// informer 캐시의 map을 공유하지 않도록 복사
+	result := make(map[string]string)
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			result[key] = value
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package kube
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestEnricher(t *testing.T) {
+	controller := true
+	cs := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+			Name: "nginx-7d9c", Namespace: "default", UID: "rs-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Deployment", Name: "nginx", UID: "deploy-uid", Controller: &controller},
+			},
+		}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nginx-7d9c-abcde", Namespace: "default", UID: "pod-uid",
+				Labels:      map[string]string{"app": "nginx", "pod-template-hash": "7d9c"},
+				Annotations: map[string]string{"team": "infra", "note": "ignored"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "nginx-7d9c", UID: "rs-uid", Controller: &controller},
+				},
+			},
+			Spec: corev1.PodSpec{
+				NodeName:       "node-1",
+				InitContainers: []corev1.Container{{Name: "init", Image: "busybox:1.36"}},
+				Containers:     []corev1.Container{{Name: "nginx", Image: "nginx:1.27"}},
+			},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1", UID: "node-uid", Labels: map[string]string{"topology.kubernetes.io/zone": "a"},
+		}},
+	)
+
+	factory := informers.NewSharedInformerFactory(cs, 0)
+	enricher := newEnricher(map[string]informers.SharedInformerFactory{metav1.NamespaceAll: factory}, factory, []string{"app"}, []string{"team"})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, enricher.synced...) {
+		t.Fatal("failed to sync enricher cache")
+	}
+
+	// Pod 이벤트는 owner chain, node, 선택한 label/annotation, image 추가
+	event := &Event{}
+	event.Regarding.Kind, event.Regarding.Namespace = "Pod", "default"
+	event.Regarding.Name, event.Regarding.UID = "nginx-7d9c-abcde", "pod-uid"
+	enricher.Enrich(event)
+	want := &Involved{
+		Owners: []Owner{
+			{Kind: "ReplicaSet", Name: "nginx-7d9c", UID: "rs-uid"},
+			{Kind: "Deployment", Name: "nginx", UID: "deploy-uid"},
+		},
+		NodeName:    "node-1",
+		Labels:      map[string]string{"app": "nginx"},
+		Annotations: map[string]string{"team": "infra"},
+		Images:      []string{"busybox:1.36", "nginx:1.27"},
+	}
+	if !reflect.DeepEqual(event.Involved, want) {
+		t.Errorf("Enrich(Pod) = %+v, want %+v", event.Involved, want)
+	}
+
+	// 같은 이름으로 다시 생성된 Pod는 추가하지 않음
+	event = &Event{}
+	event.Regarding.Kind, event.Regarding.Namespace = "Pod", "default"
+	event.Regarding.Name, event.Regarding.UID = "nginx-7d9c-abcde", "old-uid"
+	if enricher.Enrich(event); event.Involved != nil {
+		t.Errorf("Enrich(old Pod) = %+v, want nil", event.Involved)
+	}
+
+	// Node 이벤트
+	event = &Event{}
+	event.Regarding.Kind, event.Regarding.Name = "Node", "node-1"
+	if enricher.Enrich(event); event.Involved == nil || event.Involved.NodeName != "node-1" {
+		t.Errorf("Enrich(Node) = %+v, want nodeName %s", event.Involved, "node-1")
+	}
+}
@@ -1,9 +1,14 @@
 package kube
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"example.com/stradvision-project/pkg/metrics"
 	v1 "k8s.io/api/events/v1"
 )
 
@@ -62,17 +67,33 @@ import (
 
 const (
 	DefaultFlushMaxCount = 100
+	DefaultFlushMaxBytes = 5 * 1024 * 1024 // 5MB
 	DefaultFlushMaxTime  = 5 * time.Second
+	DefaultQueueSize     = 1000
 )
 
+const (
+	// 문서 ID 생성 방식
+	DocumentIDAuto       string = ""           // elasticsearch에서 자동 생성
+	DocumentIDUID        string = "uid"        // metadata.uid (이벤트별 최신 문서 하나만 유지)
+	DocumentIDUIDVersion string = "uidVersion" // metadata.uid + resourceVersion (이벤트 버전별 문서 유지)
+)
+
+// CheckDocumentID 문서 ID 생성 방식 설정 확인
+func CheckDocumentID(strategy string) error {
+	switch strategy {
+	case DocumentIDAuto, DocumentIDUID, DocumentIDUIDVersion:
+		return nil
+	default:
+		return fmt.Errorf("documentID invalid: %s", strategy)
+	}
+}
+
 type Event struct {
-	Metadata struct {
-		Name              string    `json:"name"`
-		Namespace         string    `json:"namespace"`
-		UID               string    `json:"uid"`
-		ResourceVersion   string    `json:"resourceVersion"`
-		CreationTimestamp time.Time `json:"creationTimestamp"`
-	} `json:"metadata"`
+	document
+
+	Cluster  string     `json:"cluster,omitempty"` // 수집한 cluster 이름
+	Metadata ObjectMeta `json:"metadata"`
 
 	EventTime            time.Time `json:"eventTime"`
 	RetportingController string    `json:"reportingController"`
@@ -93,21 +114,126 @@ type Event struct {
 	DeprecatedFirstTimestamp time.Time `json:"deprecatedFirstTimestamp"`
 	DeprecatedLastTimestamp  time.Time `json:"deprecatedLastTimestamp"`
 	DeprecatedCount          int       `json:"deprecatedCount"`
+
+	// 같은 이벤트가 반복된 경우에만 (events.k8s.io series)
+	Series *EventSeries `json:"series,omitempty"`
+
+	// 삭제된 이벤트의 마지막 상태를 전송한 경우에만 delete
+	Operation string `json:"operation,omitempty"`
+
+	// 대상 리소스의 owner, node, label 등 (client에서 WithEnrichment를 설정한 경우에만)
+	Involved *Involved `json:"involved,omitempty"`
+
+	// 같은 대상(regarding.uid)과 reason으로 반복된 이벤트를 client에서 묶은 경우에만 설정
+	Aggregation *Aggregation `json:"aggregation,omitempty"`
+}
+
+// EventSeries 반복된 이벤트의 누적 횟수와 마지막 발생 시각
+type EventSeries struct {
+	Count            int       `json:"count"`
+	LastObservedTime time.Time `json:"lastObservedTime"`
+}
+
+// Aggregation 집계 구간 동안 반복된 이벤트 정보
+// Count는 바로 전송한 첫 이벤트 이후 발생한 횟수 (series.count, deprecatedCount 증가분)
+type Aggregation struct {
+	Count          int       `json:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+}
+
+// OccurredAt 이벤트가 발생한 시각
+// series.lastObservedTime, deprecatedLastTimestamp, eventTime, creationTimestamp 순서로 값이 있는 것을 사용
+func (e *Event) OccurredAt() time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime
+	case !e.DeprecatedLastTimestamp.IsZero():
+		return e.DeprecatedLastTimestamp
+	case !e.EventTime.IsZero():
+		return e.EventTime
+	default:
+		return e.Metadata.CreationTimestamp
+	}
+}
+
+// Occurrences 이벤트 객체의 누적 발생 횟수
+// series.count, deprecatedCount 순서로 값이 있는 것을 사용하고 없으면 1
+func (e *Event) Occurrences() int {
+	switch {
+	case e.Series != nil && e.Series.Count > 0:
+		return e.Series.Count
+	case e.DeprecatedCount > 0:
+		return e.DeprecatedCount
+	default:
+		return 1
+	}
+}
+
+// Meta 이벤트의 metadata
+func (e *Event) Meta() *ObjectMeta {
+	return &e.Metadata
 }
 
+// DocumentID 문서 ID 생성 방식에 따라 이벤트의 문서 ID 반환
+// 같은 이벤트를 다시 처리해도 같은 ID가 생성되므로 중복 저장되지 않음
+func (e *Event) DocumentID(strategy string) string {
+	return e.Metadata.DocumentID(strategy)
+}
+
+// Version resourceVersion을 숫자로 변환하여 반환 (변환할 수 없으면 0)
+func (e *Event) Version() int64 {
+	return e.Metadata.Version()
+}
+
+// Size 이벤트의 JSON 크기
+func (e *Event) Size() int {
+	if e.size == 0 {
+		if data, err := json.Marshal(e); err == nil {
+			e.size = len(data)
+		}
+	}
+
+	return e.size
+}
+
+var (
+	// ErrBufferClosed 종료된 buffer에 이벤트를 추가한 경우의 에러
+	ErrBufferClosed = errors.New("event buffer is closed")
+	// ErrBufferFull 수신 대기열이 가득 차서 이벤트를 버린 경우의 에러 (WithDropWhenFull)
+	ErrBufferFull = errors.New("event buffer is full")
+)
+
 type EventBuffer struct {
-	EventChan chan *Event
-	Events    []*Event
+	EventChan chan Document
+	Events    []Document
+	bytes     int // Events의 JSON 크기 합계
 	closeChan chan struct{}
+	doneChan  chan struct{} // Run 루프가 마지막 flush까지 끝나면 닫힘
+	closeOnce sync.Once
+
+	// flush 임계값 (Run 루프에서만 사용, Update로 변경)
+	flushMaxCount int
+	flushMaxBytes int
+	flushInterval atomic.Int64 // time.Duration (Alive에서도 읽음)
+	dropWhenFull  atomic.Bool
+	updateChan    chan *bufferConfig
+	flushChan     chan chan struct{}
 
-	DoFunc  func([]*Event) error
-	ErrFunc func(error, []*Event)
+	running  atomic.Bool  // Run 루프 실행 여부
+	lastLoop atomic.Int64 // Run 루프가 마지막으로 동작한 시간 (unix nano)
+
+	DoFunc  func([]Document) error
+	ErrFunc func(error, []Document)
 }
 
 func NewEventBuffer(
-	doFunc func([]*Event) error,
-	errFunc func(error, []*Event),
+	doFunc func([]Document) error,
+	errFunc func(error, []Document),
+	options ...BufferOption,
 ) (*EventBuffer, error) {
+	config := fromBufferOptions(options)
+
 	if doFunc == nil {
 		return nil, fmt.Errorf("doFunc is nil")
 	}
@@ -116,50 +242,202 @@ func NewEventBuffer(
 	}
 
 	buffer := &EventBuffer{
-		EventChan: make(chan *Event),
-		Events:    make([]*Event, 0),
+		EventChan: make(chan Document, config.queueSize),
+		Events:    make([]Document, 0),
 		closeChan: make(chan struct{}),
-		DoFunc:    doFunc,
-		ErrFunc:   errFunc,
+		doneChan:  make(chan struct{}),
+
+		flushMaxCount: config.flushMaxCount,
+		flushMaxBytes: config.flushMaxBytes,
+		updateChan:    make(chan *bufferConfig),
+		flushChan:     make(chan chan struct{}),
+
+		DoFunc:  doFunc,
+		ErrFunc: errFunc,
 	}
+	buffer.flushInterval.Store(int64(config.flushInterval))
+	buffer.dropWhenFull.Store(config.dropWhenFull)
 
 	return buffer, nil
 }
 
-func (eb *EventBuffer) AddEvent(event *Event) {
-	eb.EventChan <- event
+// AddEvent buffer에 이벤트 추가
+// 종료된 buffer에는 추가하지 않고 ErrBufferClosed를 반환 (ack되지 않으므로 다시 수신됨)
+// 수신 대기열이 가득 차면 자리가 날 때까지 대기하고, WithDropWhenFull이면 버리고 ErrBufferFull을 반환
+func (eb *EventBuffer) AddEvent(event Document) error {
+	select {
+	case <-eb.closeChan:
+		return ErrBufferClosed
+	default:
+	}
+
+	if eb.dropWhenFull.Load() {
+		select {
+		case eb.EventChan <- event:
+			return nil
+		default:
+			metrics.BufferDropped.Inc()
+			return ErrBufferFull
+		}
+	}
+
+	select {
+	case <-eb.closeChan:
+		return ErrBufferClosed
+	case eb.EventChan <- event:
+		return nil
+	}
 }
 
+// Run 이벤트를 모아서 flushMaxCount, flushMaxBytes에 도달하거나 flushInterval이 지나면 flush
 func (eb *EventBuffer) Run() {
-	ticker := time.NewTicker(DefaultFlushMaxTime)
+	ticker := time.NewTicker(eb.interval())
 	defer ticker.Stop()
 
+	eb.running.Store(true)
+	defer eb.running.Store(false)
+	defer close(eb.doneChan)
+
 	for {
+		eb.lastLoop.Store(time.Now().UnixNano())
 		select {
 		case <-eb.closeChan:
+			// 종료 전에 수신 대기열과 buffer에 남은 이벤트를 마지막으로 처리
+			eb.drain()
+			if len(eb.Events) > 0 {
+				eb.flush()
+			}
 			return
 		case event := <-eb.EventChan:
-			eb.Events = append(eb.Events, event)
-			if len(eb.Events) >= DefaultFlushMaxCount {
-				if err := eb.DoFunc(eb.Events); err != nil {
-					eb.ErrFunc(err, eb.Events)
-				}
-				eb.Events = make([]*Event, 0)
+			if eb.add(event) {
+				// 크기로 flush한 직후 주기 flush가 작은 batch를 만들지 않도록 ticker 초기화
+				ticker.Reset(eb.interval())
 			}
 		case <-ticker.C:
 			if len(eb.Events) > 0 {
-				if err := eb.DoFunc(eb.Events); err != nil {
-					eb.ErrFunc(err, eb.Events)
-				}
-				eb.Events = make([]*Event, 0)
+				eb.flush()
+			}
+		case config := <-eb.updateChan:
+			eb.flushMaxCount = config.flushMaxCount
+			eb.flushMaxBytes = config.flushMaxBytes
+			eb.flushInterval.Store(int64(config.flushInterval))
+			ticker.Reset(config.flushInterval)
+			// 임계값을 줄인 경우 쌓여있는 이벤트를 바로 처리
+			if len(eb.Events) > 0 && (len(eb.Events) >= eb.flushMaxCount || eb.bytes >= eb.flushMaxBytes) {
+				eb.flush()
+			}
+		case done := <-eb.flushChan:
+			eb.drain()
+			if len(eb.Events) > 0 {
+				eb.flush()
 			}
+			close(done)
+		}
+	}
+}
+
+// add 이벤트를 buffer에 추가하고, 최대 개수나 크기에 도달하면 flush (flush 여부 반환)
+// 추가하면 최대 크기를 넘는 경우 먼저 flush하여 요청 크기를 제한
+func (eb *EventBuffer) add(event Document) bool {
+	flushed := false
+	size := event.Size()
+	if len(eb.Events) > 0 && eb.bytes+size > eb.flushMaxBytes {
+		eb.flush()
+		flushed = true
+	}
+
+	eb.Events = append(eb.Events, event)
+	eb.bytes += size
+	if len(eb.Events) >= eb.flushMaxCount || eb.bytes >= eb.flushMaxBytes {
+		eb.flush()
+		flushed = true
+	}
+
+	return flushed
+}
+
+// flush buffer에 쌓인 이벤트를 DoFunc로 처리하고 buffer 비우기
+func (eb *EventBuffer) flush() {
+	start := time.Now()
+	if err := eb.DoFunc(eb.Events); err != nil {
+		eb.ErrFunc(err, eb.Events)
+	}
+	metrics.BufferFlushSize.Observe(float64(len(eb.Events)))
+	metrics.BufferFlushDuration.Observe(time.Since(start).Seconds())
+
+	eb.Events = make([]Document, 0)
+	eb.bytes = 0
+}
+
+// drain 수신 대기열에 남은 이벤트를 buffer로 옮기기
+func (eb *EventBuffer) drain() {
+	for {
+		select {
+		case event := <-eb.EventChan:
+			eb.add(event)
+		default:
+			return
 		}
 	}
 }
 
+// Alive Run 루프가 timeout 이내에 동작했는지 확인
+// ticker가 flushInterval마다 루프를 깨우므로, 그보다 오래 멈춰있으면 DoFunc 등에서 멈춘 상태
+// timeout이 flushInterval의 2배보다 짧으면 flushInterval의 2배를 사용
+func (eb *EventBuffer) Alive(timeout time.Duration) error {
+	if interval := eb.interval(); timeout < 2*interval {
+		timeout = 2 * interval
+	}
+
+	if !eb.running.Load() {
+		return fmt.Errorf("event buffer is not running")
+	}
+
+	last := time.Unix(0, eb.lastLoop.Load())
+	if elapsed := time.Since(last); elapsed > timeout {
+		return fmt.Errorf("event buffer is stuck for %s", elapsed.Truncate(time.Second))
+	}
+
+	return nil
+}
+
+// Update 실행 중에 flush 임계값(최대 개수, 크기, 주기)과 수신 대기열이 가득 찼을 때의 동작 변경
+// 설정하지 않은 값은 기본값을 사용하고, 수신 대기열 크기(WithQueueSize)는 변경할 수 없음
+// Run 루프가 변경을 반영할 때까지 대기 (종료된 buffer는 변경하지 않음)
+func (eb *EventBuffer) Update(options ...BufferOption) {
+	config := fromBufferOptions(options)
+	eb.dropWhenFull.Store(config.dropWhenFull)
+
+	select {
+	case eb.updateChan <- config:
+	case <-eb.closeChan:
+	}
+}
+
+// Flush 수신 대기열과 buffer에 쌓인 이벤트를 바로 처리하고 끝날 때까지 대기
+// ex) kafka consumer를 교체하기 전에 수신한 이벤트를 처리하여 offset commit
+func (eb *EventBuffer) Flush() {
+	done := make(chan struct{})
+	select {
+	case eb.flushChan <- done:
+		<-done
+	case <-eb.closeChan:
+	}
+}
+
+func (eb *EventBuffer) interval() time.Duration {
+	return time.Duration(eb.flushInterval.Load())
+}
+
+// Close 이벤트 수신을 멈추고, buffer에 남은 이벤트를 처리할 때까지 대기
 func (eb *EventBuffer) Close() {
-	close(eb.closeChan)
-	close(eb.EventChan)
+	eb.closeOnce.Do(func() {
+		close(eb.closeChan)
+	})
+
+	if eb.running.Load() {
+		<-eb.doneChan
+	}
 }
 
 func ConvertEvent(object *v1.Event) *Event {
@@ -188,5 +466,12 @@ func ConvertEvent(object *v1.Event) *Event {
 	event.DeprecatedLastTimestamp = object.DeprecatedLastTimestamp.Time
 	event.DeprecatedCount = int(object.DeprecatedCount)
 
+	if object.Series != nil {
+		event.Series = &EventSeries{
+			Count:            int(object.Series.Count),
+			LastObservedTime: object.Series.LastObservedTime.Time,
+		}
+	}
+
 	return event
 }
@@ -0,0 +1,174 @@
+package kube
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventBufferClose(t *testing.T) {
+	flushed := make([]Document, 0)
+	buf, err := NewEventBuffer(
+		func(events []Document) error {
+			flushed = append(flushed, events...)
+			return nil
+		},
+		func(err error, events []Document) {},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		buf.Run()
+		close(done)
+	}()
+
+	for i := 0; i < 3; i++ {
+		if err := buf.AddEvent(&Event{}); err != nil {
+			t.Fatalf("AddEvent() = %v, want nil", err)
+		}
+	}
+
+	// 종료 시 buffer에 남은 이벤트를 모두 처리
+	buf.Close()
+	<-done
+	if len(flushed) != 3 {
+		t.Errorf("flushed = %d, want %d", len(flushed), 3)
+	}
+
+	// 종료 후에는 이벤트를 추가하지 않음
+	if err := buf.AddEvent(&Event{}); !errors.Is(err, ErrBufferClosed) {
+		t.Errorf("AddEvent() = %v, want %v", err, ErrBufferClosed)
+	}
+	buf.Close()
+}
+
+func TestEventBufferFlushMaxBytes(t *testing.T) {
+	sizes := make([]int, 0)
+	buf, err := NewEventBuffer(
+		func(events []Document) error {
+			sizes = append(sizes, len(events))
+			return nil
+		},
+		func(err error, events []Document) {},
+		WithFlushMaxBytes(250),
+		WithFlushInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		buf.Run()
+		close(done)
+	}()
+
+	// 100 bytes 이벤트 5개 -> 최대 크기를 넘지 않도록 2개씩 flush
+	for i := 0; i < 5; i++ {
+		event := &Event{}
+		event.SetSize(100)
+		if err := buf.AddEvent(event); err != nil {
+			t.Fatalf("AddEvent() = %v, want nil", err)
+		}
+	}
+	buf.Close()
+	<-done
+
+	want := []int{2, 2, 1}
+	if len(sizes) != len(want) {
+		t.Fatalf("flush sizes = %v, want %v", sizes, want)
+	}
+	for i := range want {
+		if sizes[i] != want[i] {
+			t.Errorf("flush sizes = %v, want %v", sizes, want)
+		}
+	}
+}
+
+func TestEventBufferDropWhenFull(t *testing.T) {
+	buf, err := NewEventBuffer(
+		func(events []Document) error { return nil },
+		func(err error, events []Document) {},
+		WithQueueSize(1),
+		WithDropWhenFull(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run 하지 않으므로 대기열 1개를 넘으면 버림
+	if err := buf.AddEvent(&Event{}); err != nil {
+		t.Fatalf("AddEvent() = %v, want nil", err)
+	}
+	if err := buf.AddEvent(&Event{}); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("AddEvent() = %v, want %v", err, ErrBufferFull)
+	}
+}
+
+func TestEventBufferUpdate(t *testing.T) {
+	sizes := make(chan int, 10)
+	buf, err := NewEventBuffer(
+		func(events []Document) error {
+			sizes <- len(events)
+			return nil
+		},
+		func(err error, events []Document) {},
+		WithFlushMaxCount(10),
+		WithFlushInterval(time.Hour),
+		WithQueueSize(0), // AddEvent가 반환되면 buffer에 추가된 상태
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go buf.Run()
+	defer buf.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := buf.AddEvent(&Event{}); err != nil {
+			t.Fatalf("AddEvent() = %v, want nil", err)
+		}
+	}
+
+	// 최대 개수를 줄이면 쌓여있는 이벤트를 바로 처리
+	buf.Update(WithFlushMaxCount(2), WithFlushInterval(time.Hour))
+	if size := <-sizes; size != 3 {
+		t.Errorf("flush size = %d, want 3", size)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := buf.AddEvent(&Event{}); err != nil {
+			t.Fatalf("AddEvent() = %v, want nil", err)
+		}
+	}
+	if size := <-sizes; size != 2 {
+		t.Errorf("flush size = %d, want 2", size)
+	}
+
+	// Flush는 수신 대기열까지 처리한 후 반환
+	if err := buf.AddEvent(&Event{}); err != nil {
+		t.Fatalf("AddEvent() = %v, want nil", err)
+	}
+	buf.Flush()
+	select {
+	case size := <-sizes:
+		if size != 1 {
+			t.Errorf("flush size = %d, want 1", size)
+		}
+	default:
+		t.Errorf("Flush() returned before flush")
+	}
+}
+
+func TestCheckDocumentID(t *testing.T) {
+	for _, strategy := range []string{DocumentIDAuto, DocumentIDUID, DocumentIDUIDVersion} {
+		if err := CheckDocumentID(strategy); err != nil {
+			t.Errorf("CheckDocumentID(%q) = %v, want nil", strategy, err)
+		}
+	}
+	if err := CheckDocumentID("name"); err == nil {
+		t.Error("CheckDocumentID(name) = nil, want error")
+	}
+}
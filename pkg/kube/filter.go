@@ -0,0 +1,375 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// DefaultInvolvedCacheTTL 이벤트 대상 리소스의 label 조회 결과 캐시 시간
+	DefaultInvolvedCacheTTL = 5 * time.Minute
+
+	involvedWorkers      = 4           // 대상 리소스 label을 조회하는 worker 수
+	involvedQueueSize    = 1000        // worker별 대기열 크기
+	involvedRetry        = 3           // 대상 리소스 조회 시도 횟수 (생성 직후 조회되지 않는 경우)
+	involvedRetryBackoff = time.Second // 조회 재시도 간격 (시도마다 증가)
+)
+
+// objectFilter 서버에서 필터링할 수 없는 조건을 informer handler 앞에서 필터링
+type objectFilter struct {
+	include map[string]bool // 비어있으면 모든 namespace
+	exclude map[string]bool
+
+	// 이벤트 대상 리소스(regarding, involvedObject)의 label 조건 (nil이면 필터링하지 않음)
+	involvedSelector labels.Selector
+	involved         *involvedLabels
+}
+
+// match 수집 대상인지 확인
+// namespace 조건은 namespace가 있는 리소스에만 적용 (Node 등 cluster 리소스는 항상 수집)
+func (f *objectFilter) match(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+
+	if ns := accessor.GetNamespace(); ns != "" {
+		if len(f.include) > 0 && !f.include[ns] {
+			return false
+		}
+		if f.exclude[ns] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchInvolved 이벤트 대상 리소스의 label 조건 확인 (조건이 없거나 이벤트가 아니면 true)
+func (f *objectFilter) matchInvolved(ctx context.Context, obj interface{}) (bool, error) {
+	if f.involvedSelector == nil {
+		return true, nil
+	}
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	ref, ok := involvedObject(obj)
+	if !ok {
+		return true, nil
+	}
+
+	objectLabels, err := f.involved.get(ctx, ref)
+	if err != nil {
+		return false, err
+	}
+	return f.involvedSelector.Matches(labels.Set(objectLabels)), nil
+}
+
+// handler 필터를 적용한 event handler
+// 대상 리소스의 label 조건이 있으면 informer의 이벤트 전달을 막지 않도록 involvedHandler에서 처리
+func (f *objectFilter) handler(handler cache.ResourceEventHandler, errFunc func(error)) cache.ResourceEventHandler {
+	if f.involvedSelector == nil {
+		return cache.FilteringResourceEventHandler{
+			FilterFunc: f.match,
+			Handler:    handler,
+		}
+	}
+
+	return newInvolvedHandler(f, handler, errFunc)
+}
+
+// involvedHandler 이벤트 대상 리소스의 label을 worker에서 조회하여 조건에 맞는 이벤트만 handler로 전달
+// 같은 대상 리소스의 이벤트는 같은 worker에서 처리하여 순서를 유지
+type involvedHandler struct {
+	filter  *objectFilter
+	handler cache.ResourceEventHandler
+	errFunc func(error)
+
+	queues []chan involvedItem
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// involvedItem label 조건을 확인할 이벤트와 조건이 맞으면 호출할 handler
+type involvedItem struct {
+	obj     interface{}
+	deliver func()
+}
+
+func newInvolvedHandler(filter *objectFilter, handler cache.ResourceEventHandler, errFunc func(error)) *involvedHandler {
+	h := &involvedHandler{
+		filter:  filter,
+		handler: handler,
+		errFunc: errFunc,
+		queues:  make([]chan involvedItem, involvedWorkers),
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+
+	for i := range h.queues {
+		h.queues[i] = make(chan involvedItem, involvedQueueSize)
+		h.wg.Add(1)
+		go h.worker(h.queues[i])
+	}
+
+	return h
+}
+
+func (h *involvedHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.enqueue(obj, func() { h.handler.OnAdd(obj, isInInitialList) })
+}
+
+func (h *involvedHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.enqueue(newObj, func() { h.handler.OnUpdate(oldObj, newObj) })
+}
+
+func (h *involvedHandler) OnDelete(obj interface{}) {
+	h.enqueue(obj, func() { h.handler.OnDelete(obj) })
+}
+
+// enqueue namespace 조건을 확인하고 대상 리소스 UID에 해당하는 worker로 전달
+// 대기열이 가득 차면 worker가 처리할 때까지 대기
+func (h *involvedHandler) enqueue(obj interface{}, deliver func()) {
+	if h.ctx.Err() != nil || !h.filter.match(obj) {
+		return
+	}
+
+	select {
+	case h.queues[h.shard(obj)] <- involvedItem{obj: obj, deliver: deliver}:
+	case <-h.ctx.Done():
+	}
+}
+
+// shard 대상 리소스 UID로 worker 선택
+func (h *involvedHandler) shard(obj interface{}) int {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ref, _ := involvedObject(obj)
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(ref.UID))
+	return int(hash.Sum32() % uint32(len(h.queues)))
+}
+
+func (h *involvedHandler) worker(queue chan involvedItem) {
+	defer h.wg.Done()
+
+	for {
+		select {
+		case <-h.ctx.Done():
+			return
+		case item := <-queue:
+			if h.matchWithRetry(item.obj) {
+				item.deliver()
+			}
+		}
+	}
+}
+
+// matchWithRetry 대상 리소스 조회에 실패하면 간격을 늘려가며 재시도
+// 모두 실패하면 errFunc를 호출하고 이벤트를 제외
+func (h *involvedHandler) matchWithRetry(obj interface{}) bool {
+	for attempt := 1; ; attempt++ {
+		ok, err := h.filter.matchInvolved(h.ctx, obj)
+		if err == nil {
+			return ok
+		}
+		if attempt >= involvedRetry || h.ctx.Err() != nil {
+			h.errFunc(fmt.Errorf("skip event, %w", err))
+			return false
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * involvedRetryBackoff):
+		case <-h.ctx.Done():
+			return false
+		}
+	}
+}
+
+// stop worker 종료
+// 처리 중인 이벤트의 handler 호출이 끝날 때까지 대기하며, 대기열에 남은 이벤트는 전달하지 않음
+func (h *involvedHandler) stop() {
+	h.cancel()
+	h.wg.Wait()
+}
+
+// involvedRef 이벤트 대상 리소스 정보
+type involvedRef struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	UID        types.UID
+}
+
+// involvedObject 이벤트의 대상 리소스 정보 (이벤트가 아니면 false)
+func involvedObject(obj interface{}) (involvedRef, bool) {
+	switch event := obj.(type) {
+	case *eventsv1.Event:
+		r := event.Regarding
+		return involvedRef{APIVersion: r.APIVersion, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name, UID: r.UID}, true
+	case *corev1.Event:
+		r := event.InvolvedObject
+		return involvedRef{APIVersion: r.APIVersion, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name, UID: r.UID}, true
+	default:
+		return involvedRef{}, false
+	}
+}
+
+// involvedLabels 이벤트 대상 리소스의 label을 조회하고 UID별로 캐시 (조회 실패는 캐시하지 않음)
+type involvedLabels struct {
+	client metadata.Interface
+	mapper meta.RESTMapper
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[types.UID]involvedEntry
+}
+
+type involvedEntry struct {
+	labels  map[string]string
+	expires time.Time
+}
+
+func newInvolvedLabels(client metadata.Interface, mapper meta.RESTMapper) *involvedLabels {
+	return &involvedLabels{
+		client: client,
+		mapper: mapper,
+		ttl:    DefaultInvolvedCacheTTL,
+		cache:  make(map[types.UID]involvedEntry),
+	}
+}
+
+// get 대상 리소스의 label 조회 (조회에 성공한 결과만 ttl 동안 캐시)
+func (l *involvedLabels) get(ctx context.Context, ref involvedRef) (map[string]string, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	entry, ok := l.cache[ref.UID]
+	l.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.labels, nil
+	}
+
+	objectLabels, err := l.fetch(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 만료된 항목 정리
+	for uid, e := range l.cache {
+		if now.After(e.expires) {
+			delete(l.cache, uid)
+		}
+	}
+	l.cache[ref.UID] = involvedEntry{labels: objectLabels, expires: now.Add(l.ttl)}
+
+	return objectLabels, nil
+}
+
+func (l *involvedLabels) fetch(ctx context.Context, ref involvedRef) (map[string]string, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid involved object apiVersion %s: %w", ref.APIVersion, err)
+	}
+
+	mapping, err := l.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: ref.Kind}, gv.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find involved object resource %s: %w", ref.Kind, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	object, err := l.client.Resource(mapping.Resource).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get involved object %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	return object.GetLabels(), nil
+}
+
+// isEventResource events 리소스인지 확인 (events.k8s.io/v1, core v1)
+func isEventResource(gvr schema.GroupVersionResource) bool {
+	return gvr.Resource == "events" && (gvr.Group == "events.k8s.io" || gvr.Group == "")
+}
+
+// eventFields 이벤트 field selector에서 사용할 수 있는 필드 (events.k8s.io/v1 이름: core v1 이름)
+var eventFields = map[string]string{
+	"metadata.name":             "metadata.name",
+	"metadata.namespace":        "metadata.namespace",
+	"regarding.kind":            "involvedObject.kind",
+	"regarding.namespace":       "involvedObject.namespace",
+	"regarding.name":            "involvedObject.name",
+	"regarding.uid":             "involvedObject.uid",
+	"regarding.apiVersion":      "involvedObject.apiVersion",
+	"regarding.resourceVersion": "involvedObject.resourceVersion",
+	"regarding.fieldPath":       "involvedObject.fieldPath",
+	"reason":                    "reason",
+	"reportingController":       "reportingComponent",
+	"type":                      "type",
+}
+
+// eventFieldSelector 이벤트 리소스의 API에 맞게 field selector의 필드 이름 변환
+// events.k8s.io는 regarding.*, reportingController, core v1은 involvedObject.*, reportingComponent를 사용하며
+// 두 이름 모두 설정할 수 있음 (지원하지 않는 필드는 서버에서 LIST가 실패하므로 에러 반환)
+func eventFieldSelector(selector string, gvr schema.GroupVersionResource) (string, error) {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return "", err
+	}
+
+	transformed, err := parsed.Transform(func(field, value string) (string, string, error) {
+		for eventsField, coreField := range eventFields {
+			if field != eventsField && field != coreField {
+				continue
+			}
+			if gvr.Group == "" {
+				return coreField, value, nil
+			}
+			return eventsField, value, nil
+		}
+		return "", "", fmt.Errorf("unsupported event field %s", field)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return transformed.String(), nil
+}
+
+// joinSelectors 빈 값을 제외하고 selector를 ,로 연결
+func joinSelectors(selectors ...string) string {
+	result := make([]string, 0, len(selectors))
+	for _, selector := range selectors {
+		if selector != "" {
+			result = append(result, selector)
+		}
+	}
+
+	return strings.Join(result, ",")
+}
@@ -0,0 +1,102 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	metadatafake "k8s.io/client-go/metadata/fake"
+)
+
+func TestObjectFilterNamespace(t *testing.T) {
+	filter := &objectFilter{
+		include: map[string]bool{"default": true, "stradvision": true},
+		exclude: map[string]bool{"stradvision": true},
+	}
+
+	tests := []struct {
+		obj  interface{}
+		want bool
+	}{
+		{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, true},
+		{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "stradvision"}}, false},
+		{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system"}}, false},
+		// cluster 리소스는 namespace 조건을 적용하지 않음
+		{&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}}, true},
+	}
+	for i, tt := range tests {
+		if got := filter.match(tt.obj); got != tt.want {
+			t.Errorf("[%d] match() = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestObjectFilterInvolvedLabels(t *testing.T) {
+	scheme := metadatafake.NewTestScheme()
+	if err := metav1.AddMetaToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pod := &metav1.PartialObjectMetadata{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx", UID: "nginx-uid", Labels: map[string]string{"app": "nginx"}},
+	}
+	client := metadatafake.NewSimpleMetadataClient(scheme, pod)
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Version: "v1"}})
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+
+	selector, err := labels.Parse("app=nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := &objectFilter{involvedSelector: selector, involved: newInvolvedLabels(client, mapper)}
+
+	ok, err := filter.matchInvolved(context.Background(), &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx.event"},
+		Regarding:  corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "nginx", UID: "nginx-uid"},
+	})
+	if err != nil || !ok {
+		t.Errorf("matchInvolved(nginx) = %v, %v, want true", ok, err)
+	}
+	// 대상 리소스를 조회할 수 없으면 에러를 반환하고 캐시하지 않음
+	redis := &eventsv1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis.event"},
+		Regarding:  corev1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: "default", Name: "redis", UID: "redis-uid"},
+	}
+	if _, err := filter.matchInvolved(context.Background(), redis); err == nil {
+		t.Error("matchInvolved(redis) error = nil, want error")
+	}
+	if _, ok := filter.involved.cache["redis-uid"]; ok {
+		t.Error("failed lookup cached")
+	}
+}
+
+func TestEventFieldSelector(t *testing.T) {
+	tests := []struct {
+		gvr      schema.GroupVersionResource
+		selector string
+		want     string
+		wantErr  bool
+	}{
+		{DefaultResource, "type=Warning,regarding.kind=Pod", "regarding.kind=Pod,type=Warning", false},
+		{DefaultResource, "involvedObject.name=nginx,reportingComponent=kubelet", "regarding.name=nginx,reportingController=kubelet", false},
+		{schema.GroupVersionResource{Version: "v1", Resource: "events"}, "regarding.kind=Pod,metadata.namespace!=kube-system", "metadata.namespace!=kube-system,involvedObject.kind=Pod", false},
+		{DefaultResource, "", "", false},
+		{DefaultResource, "note=failed", "", true},
+	}
+	for i, tt := range tests {
+		got, err := eventFieldSelector(tt.selector, tt.gvr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("[%d] eventFieldSelector() error = %v, wantErr %v", i, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("[%d] eventFieldSelector() = %q, want %q", i, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// informer에서 수신한 변경 종류
+	OperationAdd    string = "add"
+	OperationUpdate string = "update"
+	OperationDelete string = "delete"
+)
+
+const (
+	// SchemaVersion kafka로 전송하는 문서 형식 버전 (호환되지 않게 바뀌면 증가)
+	SchemaVersion string = "1"
+
+	// kafka 메시지 key 생성 방식 (같은 key는 같은 파티션으로 전송되어 순서가 유지됨)
+	MessageKeyInvolved string = "involved" // 이벤트는 대상 리소스 uid(regarding.uid), 그 외 리소스는 metadata.uid
+	MessageKeyUID      string = "uid"      // metadata.uid
+	MessageKeyNone     string = "none"     // key 없음
+)
+
+// MessageKey 메시지 key 생성 방식에 따라 문서의 kafka 메시지 key 반환 (기본값 involved)
+func MessageKey(doc Document, strategy string) string {
+	switch strategy {
+	case MessageKeyNone:
+		return ""
+	case MessageKeyUID:
+		return doc.Meta().UID
+	default:
+		if event, ok := doc.(*Event); ok && event.Regarding.UID != "" {
+			return event.Regarding.UID
+		}
+		return doc.Meta().UID
+	}
+}
+
+// Document buffer를 거쳐 elasticsearch에 저장되는 문서 (Event, Object)
+type Document interface {
+	Meta() *ObjectMeta
+	DocumentID(strategy string) string
+	Version() int64
+	Size() int
+	SetSize(size int)
+	SetAck(ack func())
+	Ack()
+}
+
+// ObjectMeta 리소스의 metadata
+type ObjectMeta struct {
+	Name              string    `json:"name"`
+	Namespace         string    `json:"namespace"`
+	UID               string    `json:"uid"`
+	ResourceVersion   string    `json:"resourceVersion"`
+	CreationTimestamp time.Time `json:"creationTimestamp"`
+}
+
+// DocumentID 문서 ID 생성 방식에 따라 문서 ID 반환
+func (m *ObjectMeta) DocumentID(strategy string) string {
+	switch strategy {
+	case DocumentIDUID:
+		return m.UID
+	case DocumentIDUIDVersion:
+		return m.UID + "-" + m.ResourceVersion
+	default:
+		return ""
+	}
+}
+
+// Version resourceVersion을 숫자로 변환하여 반환 (변환할 수 없으면 0)
+func (m *ObjectMeta) Version() int64 {
+	version, err := strconv.ParseInt(m.ResourceVersion, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return version
+}
+
+// document 문서 처리 상태 (직렬화 대상 아님)
+type document struct {
+	ack  func() // 문서 처리 완료 콜백
+	size int    // JSON 크기
+}
+
+// SetSize 문서의 JSON 크기 설정
+// 수신한 메시지 크기를 알고 있으면 설정하여 Size에서 다시 직렬화하지 않도록 함
+func (d *document) SetSize(size int) {
+	d.size = size
+}
+
+// SetAck 문서 처리가 완료되었을 때 호출할 콜백 설정
+func (d *document) SetAck(ack func()) {
+	d.ack = ack
+}
+
+// Ack 문서 처리 완료 (kafka offset commit 대상이 됨)
+func (d *document) Ack() {
+	if d.ack != nil {
+		d.ack()
+	}
+}
+
+// Object Event 이외의 리소스(Pod, Node, Deployment, CRD 등)를 담는 문서
+type Object struct {
+	document
+
+	Cluster    string     `json:"cluster,omitempty"` // 수집한 cluster 이름
+	Kind       string     `json:"kind"`
+	APIVersion string     `json:"apiVersion"`
+	Metadata   ObjectMeta `json:"metadata"`
+	Timestamp  time.Time  `json:"timestamp"`           // 수집 시간
+	Operation  string     `json:"operation,omitempty"` // add, update, delete
+
+	Object json.RawMessage `json:"object"` // 리소스 원본 (managedFields 제외)
+}
+
+// Meta 리소스의 metadata
+func (o *Object) Meta() *ObjectMeta {
+	return &o.Metadata
+}
+
+// DocumentID 문서 ID 생성 방식에 따라 리소스의 문서 ID 반환
+func (o *Object) DocumentID(strategy string) string {
+	return o.Metadata.DocumentID(strategy)
+}
+
+// Version resourceVersion을 숫자로 변환하여 반환 (변환할 수 없으면 0)
+func (o *Object) Version() int64 {
+	return o.Metadata.Version()
+}
+
+// Size 문서의 JSON 크기
+func (o *Object) Size() int {
+	if o.size == 0 {
+		if data, err := json.Marshal(o); err == nil {
+			o.size = len(data)
+		}
+	}
+
+	return o.size
+}
+
+// ConvertObject informer에서 받은 리소스(typed, unstructured)를 Object로 변환
+func ConvertObject(obj interface{}) (*Object, error) {
+	runtimeObj, ok := obj.(runtime.Object)
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type: %T", obj)
+	}
+	// informer 캐시의 객체는 수정하면 안 되므로 복사해서 사용
+	runtimeObj = runtimeObj.DeepCopyObject()
+
+	accessor, err := meta.Accessor(runtimeObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access object metadata: %w", err)
+	}
+	accessor.SetManagedFields(nil)
+
+	gvk := runtimeObj.GetObjectKind().GroupVersionKind()
+	if gvk.Empty() {
+		// typed informer의 객체는 TypeMeta가 비어있으므로 scheme에서 조회
+		gvks, _, err := scheme.Scheme.ObjectKinds(runtimeObj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find object kind: %w", err)
+		}
+		gvk = gvks[0]
+	}
+
+	data, err := json.Marshal(runtimeObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object: %w", err)
+	}
+
+	object := &Object{
+		Kind:       gvk.Kind,
+		APIVersion: gvk.GroupVersion().String(),
+		Timestamp:  time.Now(),
+		Object:     data,
+	}
+	object.Metadata.Name = accessor.GetName()
+	object.Metadata.Namespace = accessor.GetNamespace()
+	object.Metadata.UID = string(accessor.GetUID())
+	object.Metadata.ResourceVersion = accessor.GetResourceVersion()
+	object.Metadata.CreationTimestamp = accessor.GetCreationTimestamp().Time
+
+	return object, nil
+}
+
+// volatileFields 실제 변경 없이 주기적으로 바뀌어 변경 여부 비교에서 제외하는 필드
+var volatileFields = map[string]bool{
+	"resourceVersion":   true,
+	"managedFields":     true,
+	"lastHeartbeatTime": true, // Node condition (kubelet 상태 보고)
+	"renewTime":         true, // Lease
+}
+
+// ObjectChanged 이전 리소스와 비교하여 실제로 변경되었는지 여부
+// resourceVersion, managedFields, heartbeat 시각처럼 변경 없이 주기적으로 바뀌는 필드는 제외하고 비교
+// 비교할 수 없으면 변경된 것으로 판단
+func ObjectChanged(oldObj, newObj interface{}) bool {
+	oldContent, err := objectContent(oldObj)
+	if err != nil {
+		return true
+	}
+	newContent, err := objectContent(newObj)
+	if err != nil {
+		return true
+	}
+
+	return !reflect.DeepEqual(oldContent, newContent)
+}
+
+// objectContent 리소스를 map으로 변환하고 volatileFields 제거 (informer 캐시의 객체는 수정하지 않음)
+func objectContent(obj interface{}) (map[string]interface{}, error) {
+	var content map[string]interface{}
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		content = runtime.DeepCopyJSON(o.UnstructuredContent())
+	case runtime.Object:
+		var err error
+		if content, err = runtime.DefaultUnstructuredConverter.ToUnstructured(o); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported object type: %T", obj)
+	}
+
+	removeVolatile(content)
+	return content, nil
+}
+
+func removeVolatile(v interface{}) {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if volatileFields[key] {
+				delete(value, key)
+				continue
+			}
+			removeVolatile(item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			removeVolatile(item)
+		}
+	}
+}
+
+// Decode kafka 메시지를 문서로 변환
+// object 필드가 있으면 Object, 없으면 Event로 변환
+func Decode(data []byte) (Document, error) {
+	probe := struct {
+		Object json.RawMessage `json:"object"`
+	}{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	var doc Document = &Event{}
+	if probe.Object != nil {
+		doc = &Object{}
+	}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	doc.SetSize(len(data))
+
+	return doc, nil
+}
+
+// IndexName 문서를 저장할 index 이름
+// Event는 base, 그 외 리소스는 base-object-{kind} (ex. event-object-pod)
+// 리소스 index는 이벤트 rollover index(base-000001 등)와 다른 index template을 사용하도록 구분
+func IndexName(base string, doc Document) string {
+	if object, ok := doc.(*Object); ok && object.Kind != "" {
+		return base + "-object-" + strings.ToLower(object.Kind)
+	}
+
+	return base
+}
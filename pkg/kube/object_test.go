@@ -0,0 +1,138 @@
+package kube
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConvertObject(t *testing.T) {
+	// typed 객체는 scheme에서 kind 조회
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "nginx", Namespace: "default", UID: "pod-uid", ResourceVersion: "10",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	object, err := ConvertObject(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.Kind != "Pod" || object.APIVersion != "v1" {
+		t.Errorf("kind = %s %s, want Pod v1", object.Kind, object.APIVersion)
+	}
+	if object.Metadata.UID != "pod-uid" || object.Version() != 10 {
+		t.Errorf("metadata = %+v", object.Metadata)
+	}
+	if len(pod.ManagedFields) != 1 {
+		t.Error("informer cache object is modified")
+	}
+
+	converted := &corev1.Pod{}
+	if err := json.Unmarshal(object.Object, converted); err != nil {
+		t.Fatal(err)
+	}
+	if converted.Status.Phase != corev1.PodRunning || len(converted.ManagedFields) != 0 {
+		t.Errorf("object = %+v", converted)
+	}
+
+	// unstructured 객체는 객체의 kind 사용
+	topic := &unstructured.Unstructured{}
+	topic.SetAPIVersion("kafka.strimzi.io/v1beta2")
+	topic.SetKind("KafkaTopic")
+	topic.SetName("event")
+	object, err = ConvertObject(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.Kind != "KafkaTopic" || object.APIVersion != "kafka.strimzi.io/v1beta2" {
+		t.Errorf("kind = %s %s, want KafkaTopic kafka.strimzi.io/v1beta2", object.Kind, object.APIVersion)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	object := &Object{Kind: "Pod", APIVersion: "v1", Object: json.RawMessage(`{"spec":{}}`)}
+	event := &Event{}
+	event.Metadata.Name = "event"
+
+	tests := []struct {
+		doc   Document
+		index string
+	}{
+		{object, "event-object-pod"},
+		{event, "event"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc, err := Decode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Size() != len(data) {
+			t.Errorf("Size() = %d, want %d", doc.Size(), len(data))
+		}
+		if got := IndexName("event", doc); got != tt.index {
+			t.Errorf("IndexName() = %s, want %s", got, tt.index)
+		}
+	}
+}
+
+func TestMessageKey(t *testing.T) {
+	event := &Event{}
+	event.Metadata.UID = "event-uid"
+	event.Regarding.UID = "pod-uid"
+	object := &Object{}
+	object.Metadata.UID = "object-uid"
+
+	tests := []struct {
+		doc      Document
+		strategy string
+		want     string
+	}{
+		{event, "", "pod-uid"},
+		{event, MessageKeyInvolved, "pod-uid"},
+		{event, MessageKeyUID, "event-uid"},
+		{event, MessageKeyNone, ""},
+		{object, MessageKeyInvolved, "object-uid"},
+		{object, MessageKeyUID, "object-uid"},
+	}
+	for _, tt := range tests {
+		if got := MessageKey(tt.doc, tt.strategy); got != tt.want {
+			t.Errorf("MessageKey(%T, %q) = %q, want %q", tt.doc, tt.strategy, got, tt.want)
+		}
+	}
+}
+
+func TestObjectChanged(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1", ResourceVersion: "10"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue, LastHeartbeatTime: metav1.Unix(100, 0)},
+		}},
+	}
+
+	// heartbeat 시각과 resourceVersion만 바뀌면 변경되지 않은 것으로 판단
+	heartbeat := node.DeepCopy()
+	heartbeat.ResourceVersion = "11"
+	heartbeat.Status.Conditions[0].LastHeartbeatTime = metav1.Unix(200, 0)
+	if ObjectChanged(node, heartbeat) {
+		t.Errorf("ObjectChanged(heartbeat) = true, want false")
+	}
+
+	notReady := heartbeat.DeepCopy()
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	if !ObjectChanged(heartbeat, notReady) {
+		t.Errorf("ObjectChanged(not ready) = false, want true")
+	}
+	if heartbeat.ResourceVersion != "11" {
+		t.Error("informer cache object is modified")
+	}
+}
@@ -1,16 +1,41 @@
 package kube
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultResource 기본 수집 리소스 (events.k8s.io/v1 events)
+var DefaultResource = schema.GroupVersionResource{Group: "events.k8s.io", Version: "v1", Resource: "events"}
 
 type clientConfig struct {
 	kubeConfig string
+	context    string
 	resyncTime time.Duration
+	resources  []schema.GroupVersionResource
+
+	// 수집 대상 필터
+	namespaces        []string
+	excludeNamespaces []string
+	fieldSelector     string
+	labelSelector     string
+
+	// 이벤트 대상 리소스 정보 추가
+	enrich            bool
+	enrichLabels      []string
+	enrichAnnotations []string
+
+	errFunc func(error) // 이벤트 대상 리소스 label 조회 실패 등 수집 중 에러
 }
 
 func defaultConfig() *clientConfig {
 	return &clientConfig{
 		kubeConfig: "",
 		resyncTime: 0,
+		resources:  []schema.GroupVersionResource{DefaultResource},
+		errFunc:    func(err error) {},
 	}
 }
 
@@ -33,6 +58,13 @@ func WithResyncTime(resyncTime time.Duration) Option {
 	}
 }
 
+// WithContext kubeconfig context 설정 (없으면 current-context)
+func WithContext(context string) Option {
+	return func(c *clientConfig) {
+		c.context = context
+	}
+}
+
 // WithKubeConfig kubeconfig 설정
 func WithKubeConfig(kubeConfig string) Option {
 	return func(c *clientConfig) {
@@ -41,3 +73,72 @@ func WithKubeConfig(kubeConfig string) Option {
 		}
 	}
 }
+
+// WithResources 수집할 리소스 설정
+func WithResources(resources ...schema.GroupVersionResource) Option {
+	return func(c *clientConfig) {
+		if len(resources) > 0 {
+			c.resources = resources
+		}
+	}
+}
+
+// WithNamespaces 수집할 namespace 설정 (없으면 모든 namespace)
+// 하나만 설정하면 이벤트는 서버에서 해당 namespace만 조회
+func WithNamespaces(namespaces ...string) Option {
+	return func(c *clientConfig) {
+		c.namespaces = append(c.namespaces, nonEmpty(namespaces)...)
+	}
+}
+
+// WithExcludeNamespaces 수집하지 않을 namespace 설정 (ex. kube-system)
+func WithExcludeNamespaces(namespaces ...string) Option {
+	return func(c *clientConfig) {
+		c.excludeNamespaces = append(c.excludeNamespaces, nonEmpty(namespaces)...)
+	}
+}
+
+// WithFieldSelector 이벤트 field selector 설정 (ex. type=Warning,regarding.kind=Pod)
+// 이벤트 리소스에만 서버에서 적용하며, core v1 events에는 involvedObject.*로 변환하여 적용
+func WithFieldSelector(selector string) Option {
+	return func(c *clientConfig) {
+		c.fieldSelector = selector
+	}
+}
+
+// WithLabelSelector label selector 설정 (ex. app=nginx,tier!=cache)
+// 이벤트 이외의 리소스는 서버에서 적용하고, 이벤트는 대상 리소스(regarding)의 label로 필터링
+func WithLabelSelector(selector string) Option {
+	return func(c *clientConfig) {
+		c.labelSelector = selector
+	}
+}
+
+// WithEnrichment 이벤트 대상 리소스(Pod, ReplicaSet, Node)의 owner chain, node, label, annotation, container image 추가
+// labels가 없으면 모든 label을 추가하고, annotations는 설정한 key만 추가
+func WithEnrichment(labels, annotations []string) Option {
+	return func(c *clientConfig) {
+		c.enrich = true
+		c.enrichLabels = nonEmpty(labels)
+		c.enrichAnnotations = nonEmpty(annotations)
+	}
+}
+
+// WithErrorFunc 수집 중 에러가 발생했을 때 호출할 함수 설정 (ex. 이벤트 대상 리소스의 label 조회 실패로 이벤트 제외)
+func WithErrorFunc(errFunc func(error)) Option {
+	return func(c *clientConfig) {
+		if errFunc != nil {
+			c.errFunc = errFunc
+		}
+	}
+}
+
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
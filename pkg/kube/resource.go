@@ -0,0 +1,43 @@
+package kube
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ParseResource "group/version/resource" 형식의 문자열을 GroupVersionResource로 변환
+// core 그룹은 "version/resource" 형식 사용
+// ex) events.k8s.io/v1/events, v1/pods, apps/v1/deployments, kafka.strimzi.io/v1beta2/kafkatopics
+func ParseResource(resource string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(strings.TrimSpace(resource), "/")
+	for _, part := range parts {
+		if part == "" {
+			return schema.GroupVersionResource{}, fmt.Errorf("invalid resource: %s", resource)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid resource: %s", resource)
+	}
+}
+
+// ParseResources 문자열 목록을 GroupVersionResource 목록으로 변환
+func ParseResources(resources []string) ([]schema.GroupVersionResource, error) {
+	result := make([]schema.GroupVersionResource, 0, len(resources))
+	for _, resource := range resources {
+		gvr, err := ParseResource(resource)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, gvr)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParseResource(t *testing.T) {
+	tests := []struct {
+		resource string
+		want     schema.GroupVersionResource
+		wantErr  bool
+	}{
+		{"events.k8s.io/v1/events", DefaultResource, false},
+		{"v1/pods", schema.GroupVersionResource{Version: "v1", Resource: "pods"}, false},
+		{"apps/v1/deployments", schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}, false},
+		{"pods", schema.GroupVersionResource{}, true},
+		{"apps//deployments", schema.GroupVersionResource{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseResource(tt.resource)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseResource(%s) error = %v, wantErr %v", tt.resource, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseResource(%s) = %v, want %v", tt.resource, got, tt.want)
+		}
+	}
+}
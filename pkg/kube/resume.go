@@ -0,0 +1,332 @@
+package kube
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	DefaultResumeMaxSize      = 10000
+	DefaultResumeSaveInterval = 10 * time.Second
+
+	// ResumeConfigMapKey ConfigMap에 처리 기록을 저장하는 key
+	ResumeConfigMapKey string = "resume.json"
+
+	resumeTimeout = 5 * time.Second
+)
+
+// ResumeStore 처리 기록을 저장하는 저장소
+// 저장된 기록이 없으면 Load는 nil을 반환
+type ResumeStore interface {
+	Load() ([]byte, error)
+	Save(data []byte) error
+}
+
+// fileStore 로컬 파일에 처리 기록 저장
+type fileStore struct {
+	path string
+}
+
+// NewFileStore 로컬 파일 저장소 생성
+func NewFileStore(path string) ResumeStore {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resume file: %w", err)
+	}
+	return data, nil
+}
+
+// Save 임시 파일에 기록한 뒤 rename하여 중간에 종료되어도 기록이 깨지지 않도록 함
+func (s *fileStore) Save(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create resume directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write resume file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to rename resume file: %w", err)
+	}
+	return nil
+}
+
+// configMapStore ConfigMap에 처리 기록 저장
+// 여러 replica가 같은 기록을 공유하므로 leader가 바뀌어도 이어서 사용 가능
+type configMapStore struct {
+	cs        kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapStore ConfigMap 저장소 생성
+func NewConfigMapStore(cs kubernetes.Interface, namespace, name string) ResumeStore {
+	return &configMapStore{cs: cs, namespace: namespace, name: name}
+}
+
+func (s *configMapStore) Load() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), resumeTimeout)
+	defer cancel()
+
+	cm, err := s.cs.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resume configmap: %w", err)
+	}
+	return []byte(cm.Data[ResumeConfigMapKey]), nil
+}
+
+func (s *configMapStore) Save(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), resumeTimeout)
+	defer cancel()
+
+	cms := s.cs.CoreV1().ConfigMaps(s.namespace)
+	cm, err := cms.Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: s.namespace, Name: s.name},
+			Data:       map[string]string{ResumeConfigMapKey: string(data)},
+		}
+		if _, err := cms.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create resume configmap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get resume configmap: %w", err)
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[ResumeConfigMapKey] = string(data)
+	if _, err := cms.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update resume configmap: %w", err)
+	}
+	return nil
+}
+
+// resumeEntry 저장 형식 (최근에 기록하지 않은 순서)
+type resumeEntry struct {
+	UID             string `json:"uid"`
+	ResourceVersion int64  `json:"resourceVersion"`
+}
+
+// Resume 전송한 리소스의 UID별 최신 resourceVersion 기록
+// 재시작 후 informer의 최초 LIST로 다시 수신한 리소스 중 이미 전송한 것을 제외하는 데 사용
+// 최대 maxSize개를 유지하고, 넘으면 가장 오래전에 기록한 UID부터 제거 (LRU)
+type Resume struct {
+	store ResumeStore
+
+	mu       sync.Mutex
+	versions map[string]*list.Element // UID → order의 *resumeEntry
+	order    *list.List               // 최근에 기록하지 않은 순서
+	dirty    bool
+
+	maxSize      int
+	saveInterval time.Duration
+	errFunc      func(error)
+
+	running   atomic.Bool
+	closeChan chan struct{}
+	doneChan  chan struct{}
+	closeOnce sync.Once
+}
+
+// NewResume Resume 생성 (기록은 Load로 읽음)
+// store가 nil이면 저장하지 않고 메모리에서만 기록
+func NewResume(store ResumeStore, options ...ResumeOption) *Resume {
+	config := fromResumeOptions(options)
+
+	return &Resume{
+		store:        store,
+		versions:     make(map[string]*list.Element),
+		order:        list.New(),
+		maxSize:      config.maxSize,
+		saveInterval: config.saveInterval,
+		errFunc:      config.errFunc,
+		closeChan:    make(chan struct{}),
+		doneChan:     make(chan struct{}),
+	}
+}
+
+// Load 저장소에서 기록을 읽어 현재 기록을 대체
+// informer를 시작하기 전에 호출해야 함 (leader election을 사용하면 leader가 된 후)
+func (r *Resume) Load() error {
+	if r.store == nil {
+		return nil
+	}
+	data, err := r.store.Load()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]resumeEntry, 0)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("failed to unmarshal resume data: %w", err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versions = make(map[string]*list.Element, len(entries))
+	r.order = list.New()
+	r.dirty = false
+	for _, entry := range entries {
+		r.mark(entry.UID, entry.ResourceVersion)
+	}
+	return nil
+}
+
+// Processed 같은 UID의 같거나 최신 resourceVersion을 이미 전송했는지 여부
+func (r *Resume) Processed(doc Document) bool {
+	meta := doc.Meta()
+	if meta.UID == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.versions[meta.UID]
+	return ok && doc.Version() <= e.Value.(*resumeEntry).ResourceVersion
+}
+
+// Contains 같은 UID를 전송한 기록이 있는지 여부 (resourceVersion 무관)
+func (r *Resume) Contains(doc Document) bool {
+	meta := doc.Meta()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.versions[meta.UID]
+	return ok
+}
+
+// Mark 전송한 리소스 기록
+func (r *Resume) Mark(doc Document) {
+	meta := doc.Meta()
+	if meta.UID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mark(meta.UID, doc.Version())
+}
+
+// mark UID의 resourceVersion을 기록하고 가장 최근에 기록한 UID로 이동
+func (r *Resume) mark(uid string, version int64) {
+	if e, ok := r.versions[uid]; ok {
+		entry := e.Value.(*resumeEntry)
+		if version > entry.ResourceVersion {
+			entry.ResourceVersion = version
+		}
+		r.order.MoveToBack(e)
+		r.dirty = true
+		return
+	}
+
+	r.versions[uid] = r.order.PushBack(&resumeEntry{UID: uid, ResourceVersion: version})
+	r.dirty = true
+
+	for r.order.Len() > r.maxSize {
+		oldest := r.order.Front()
+		delete(r.versions, oldest.Value.(*resumeEntry).UID)
+		r.order.Remove(oldest)
+	}
+}
+
+// Len 기록된 UID 개수
+func (r *Resume) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.versions)
+}
+
+// Save 변경된 기록이 있으면 저장소에 저장
+func (r *Resume) Save() error {
+	r.mu.Lock()
+	if !r.dirty || r.store == nil {
+		r.mu.Unlock()
+		return nil
+	}
+	entries := make([]resumeEntry, 0, r.order.Len())
+	for e := r.order.Front(); e != nil; e = e.Next() {
+		entries = append(entries, *e.Value.(*resumeEntry))
+	}
+	r.dirty = false
+	r.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal resume data: %w", err)
+	}
+	if err := r.store.Save(data); err != nil {
+		r.mu.Lock()
+		r.dirty = true
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Run saveInterval마다 기록 저장
+func (r *Resume) Run() {
+	r.running.Store(true)
+	defer close(r.doneChan)
+
+	ticker := time.NewTicker(r.saveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.closeChan:
+			return
+		case <-ticker.C:
+			if err := r.Save(); err != nil {
+				r.errFunc(err)
+			}
+		}
+	}
+}
+
+// Close 주기 저장을 멈추고 마지막으로 기록 저장
+// 변경된 기록이 없으면 저장하지 않으므로 standby가 leader의 기록을 덮어쓰지 않음
+func (r *Resume) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.closeChan)
+	})
+	if r.running.Load() {
+		<-r.doneChan
+	}
+
+	return r.Save()
+}
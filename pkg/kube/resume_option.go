@@ -0,0 +1,55 @@
+package kube
+
+import "time"
+
+type resumeConfig struct {
+	maxSize      int
+	saveInterval time.Duration
+	errFunc      func(error)
+}
+
+func defaultResumeConfig() *resumeConfig {
+	return &resumeConfig{
+		maxSize:      DefaultResumeMaxSize,
+		saveInterval: DefaultResumeSaveInterval,
+		errFunc:      func(err error) {},
+	}
+}
+
+type ResumeOption func(*resumeConfig)
+
+func fromResumeOptions(options []ResumeOption) *resumeConfig {
+	config := defaultResumeConfig()
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithResumeMaxSize 기록할 최대 UID 개수 설정
+// ConfigMap은 최대 1MB이므로 UID당 약 70byte를 고려하여 설정
+func WithResumeMaxSize(maxSize int) ResumeOption {
+	return func(c *resumeConfig) {
+		if maxSize > 0 {
+			c.maxSize = maxSize
+		}
+	}
+}
+
+// WithResumeSaveInterval 기록 저장 주기 설정
+func WithResumeSaveInterval(interval time.Duration) ResumeOption {
+	return func(c *resumeConfig) {
+		if interval > 0 {
+			c.saveInterval = interval
+		}
+	}
+}
+
+// WithResumeErrorFunc 주기 저장 실패 시 호출할 함수 설정
+func WithResumeErrorFunc(errFunc func(error)) ResumeOption {
+	return func(c *resumeConfig) {
+		if errFunc != nil {
+			c.errFunc = errFunc
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package kube
+
+import (
+	"path/filepath"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testEvent(uid, resourceVersion string) *Event {
+	event := &Event{}
+	event.Metadata.UID = uid
+	event.Metadata.ResourceVersion = resourceVersion
+	return event
+}
+
+func TestResume(t *testing.T) {
+	stores := map[string]ResumeStore{
+		"file":      NewFileStore(filepath.Join(t.TempDir(), "resume", "resume.json")),
+		"configmap": NewConfigMapStore(fake.NewSimpleClientset(), "stradvision", "client-resume"),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			resume := NewResume(store, WithResumeMaxSize(2))
+			// 저장된 기록이 없으면 빈 기록으로 시작
+			if err := resume.Load(); err != nil {
+				t.Fatal(err)
+			}
+
+			resume.Mark(testEvent("a", "10"))
+			resume.Mark(testEvent("b", "20"))
+			resume.Mark(testEvent("a", "11"))
+			if err := resume.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			// 재시작 후 기록을 읽어 이미 전송한 버전은 제외
+			restarted := NewResume(store, WithResumeMaxSize(2))
+			if err := restarted.Load(); err != nil {
+				t.Fatal(err)
+			}
+			tests := []struct {
+				event *Event
+				want  bool
+			}{
+				{testEvent("a", "10"), true},
+				{testEvent("a", "11"), true},
+				{testEvent("a", "12"), false},
+				{testEvent("b", "20"), true},
+				{testEvent("c", "1"), false},
+			}
+			for _, tt := range tests {
+				if got := restarted.Processed(tt.event); got != tt.want {
+					t.Errorf("Processed(%s/%s) = %v, want %v",
+						tt.event.Metadata.UID, tt.event.Metadata.ResourceVersion, got, tt.want)
+				}
+			}
+
+			// 최대 개수를 넘으면 가장 오래전에 기록한 UID부터 제거 (a는 b보다 나중에 다시 기록됨)
+			restarted.Mark(testEvent("c", "1"))
+			if restarted.Len() != 2 || restarted.Processed(testEvent("b", "20")) || !restarted.Processed(testEvent("a", "11")) {
+				t.Errorf("Len() = %d, want least recently marked uid evicted", restarted.Len())
+			}
+		})
+	}
+}
+
+func TestResumeMemory(t *testing.T) {
+	// store가 없으면 저장하지 않고 메모리에서만 기록
+	resume := NewResume(nil)
+	if err := resume.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	resume.Mark(testEvent("a", "10"))
+	if err := resume.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 삭제 시점의 resourceVersion이 더 커도 같은 UID를 전송한 기록이 있으면 Contains
+	if !resume.Contains(testEvent("a", "20")) || resume.Processed(testEvent("a", "20")) {
+		t.Errorf("Contains(a/20), Processed(a/20) = %v, %v, want %v, %v",
+			resume.Contains(testEvent("a", "20")), resume.Processed(testEvent("a", "20")), true, false)
+	}
+	if resume.Contains(testEvent("b", "1")) {
+		t.Errorf("Contains(b/1) = %v, want %v", true, false)
+	}
+}
@@ -0,0 +1,96 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"example.com/stradvision-project/pkg/metrics"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// Elector coordination.k8s.io Lease 기반 leader election
+// 여러 replica 중 lease를 획득한 하나만 leader로 동작
+type Elector struct {
+	le       *leaderelection.LeaderElector
+	identity string
+	leading  atomic.Bool
+	leader   atomic.Value // 현재 leader identity
+}
+
+// NewElector Elector 생성
+// namespace/name: lease 리소스 위치
+// identity: replica 식별자 (pod 이름)
+// onStarted: leader가 되면 호출, onStopped: leader를 잃으면 호출
+func NewElector(
+	cs kubernetes.Interface,
+	namespace, name, identity string,
+	onStarted func(ctx context.Context),
+	onStopped func(),
+	options ...Option,
+) (*Elector, error) {
+	config := fromOptions(options)
+	e := &Elector{identity: identity}
+	e.leader.Store("")
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta:  metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Client:     cs.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.leaseDuration,
+		RenewDeadline:   config.renewDeadline,
+		RetryPeriod:     config.retryPeriod,
+		ReleaseOnCancel: true, // 정상 종료 시 lease를 반납하여 standby가 바로 leader가 되도록 함
+		Name:            name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				e.leading.Store(true)
+				metrics.Leader.Set(1)
+				onStarted(ctx)
+			},
+			OnStoppedLeading: func() {
+				e.leading.Store(false)
+				metrics.Leader.Set(0)
+				onStopped()
+			},
+			OnNewLeader: func(identity string) {
+				e.leader.Store(identity)
+				config.newLeaderFunc(identity)
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create leader elector: %w", err)
+	}
+	e.le = le
+
+	return e, nil
+}
+
+// Run leader election 실행
+// ctx가 취소되거나 leader를 잃으면 반환
+func (e *Elector) Run(ctx context.Context) {
+	e.le.Run(ctx)
+}
+
+// IsLeader 현재 replica가 leader인지 여부
+func (e *Elector) IsLeader() bool {
+	return e.leading.Load()
+}
+
+// Leader 현재 leader identity (알 수 없으면 빈 문자열)
+func (e *Elector) Leader() string {
+	return e.leader.Load().(string)
+}
+
+// Identity 현재 replica의 identity
+func (e *Elector) Identity() string {
+	return e.identity
+}
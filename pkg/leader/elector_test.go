@@ -0,0 +1,80 @@
+package leader
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestElector(t *testing.T) {
+	cs := fake.NewSimpleClientset()
+	options := []Option{
+		WithLeaseDuration(time.Second),
+		WithRenewDeadline(500 * time.Millisecond),
+		WithRetryPeriod(100 * time.Millisecond),
+	}
+
+	started := make(chan string, 2)
+	newElector := func(identity string) *Elector {
+		e, err := NewElector(cs, "stradvision", "client", identity,
+			func(ctx context.Context) { started <- identity },
+			func() {},
+			options...,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	// 먼저 실행한 replica가 leader
+	first := newElector("client-0")
+	firstCtx, firstCancel := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		first.Run(firstCtx)
+		close(firstDone)
+	}()
+
+	select {
+	case identity := <-started:
+		if identity != "client-0" {
+			t.Fatalf("leader = %s, want client-0", identity)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("client-0 did not become leader")
+	}
+	if !first.IsLeader() {
+		t.Error("client-0 IsLeader() = false, want true")
+	}
+
+	second := newElector("client-1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go second.Run(ctx)
+
+	time.Sleep(300 * time.Millisecond)
+	if second.IsLeader() {
+		t.Error("client-1 IsLeader() = true, want false")
+	}
+	if second.Leader() != "client-0" {
+		t.Errorf("client-1 Leader() = %s, want client-0", second.Leader())
+	}
+
+	// leader가 종료되면 standby가 leader가 됨
+	firstCancel()
+	<-firstDone
+	select {
+	case identity := <-started:
+		if identity != "client-1" {
+			t.Fatalf("leader = %s, want client-1", identity)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("client-1 did not take over")
+	}
+	if first.IsLeader() {
+		t.Error("client-0 IsLeader() = true, want false")
+	}
+}
@@ -0,0 +1,66 @@
+package leader
+
+import "time"
+
+type electorConfig struct {
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+
+	newLeaderFunc func(identity string)
+}
+
+func defaultConfig() *electorConfig {
+	return &electorConfig{
+		leaseDuration: 15 * time.Second, // leader가 갱신하지 않으면 standby가 lease를 가져가기까지의 시간
+		renewDeadline: 10 * time.Second, // leader가 이 시간 안에 갱신하지 못하면 leader를 포기
+		retryPeriod:   2 * time.Second,  // lease 획득, 갱신 시도 간격
+		newLeaderFunc: func(identity string) {},
+	}
+}
+
+type Option func(*electorConfig)
+
+func fromOptions(options []Option) *electorConfig {
+	config := defaultConfig()
+	for _, option := range options {
+		option(config)
+	}
+	return config
+}
+
+// WithLeaseDuration lease 유지 시간 설정
+func WithLeaseDuration(duration time.Duration) Option {
+	return func(c *electorConfig) {
+		if duration > 0 {
+			c.leaseDuration = duration
+		}
+	}
+}
+
+// WithRenewDeadline lease 갱신 제한 시간 설정 (leaseDuration보다 짧아야 함)
+func WithRenewDeadline(deadline time.Duration) Option {
+	return func(c *electorConfig) {
+		if deadline > 0 {
+			c.renewDeadline = deadline
+		}
+	}
+}
+
+// WithRetryPeriod lease 획득, 갱신 시도 간격 설정
+func WithRetryPeriod(period time.Duration) Option {
+	return func(c *electorConfig) {
+		if period > 0 {
+			c.retryPeriod = period
+		}
+	}
+}
+
+// WithNewLeaderFunc leader가 바뀌었을 때 호출할 함수 설정 (로그 등)
+func WithNewLeaderFunc(newLeaderFunc func(identity string)) Option {
+	return func(c *electorConfig) {
+		if newLeaderFunc != nil {
+			c.newLeaderFunc = newLeaderFunc
+		}
+	}
+}
@@ -3,18 +3,23 @@ package logger
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+// level 실행 중에 SetLevel로 변경할 수 있는 로그 레벨
+var level = zap.NewAtomicLevel()
+
 func InitLogger(appName string, options ...Option) error {
 	c := fromOptions(appName, options...)
+	level.SetLevel(c.level)
 	writer = zap.New(
 		zapcore.NewCore(
 			c.encoder,
 			zapcore.NewMultiWriteSyncer(append([]zapcore.WriteSyncer{zapcore.AddSync(os.Stdout)}, zapcore.AddSync(&c.logger))...),
-			c.level,
+			level,
 		),
 	)
 
@@ -24,3 +29,35 @@ func InitLogger(appName string, options ...Option) error {
 
 	return nil
 }
+
+// SetLevel 실행 중에 로그 레벨 변경 (ex. 설정 reload)
+func SetLevel(l string) error {
+	parsed, err := ParseLevel(l)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
+// ParseLevel 로그 레벨 문자열 변환 (빈 문자열은 INFO)
+func ParseLevel(l string) (zapcore.Level, error) {
+	switch strings.ToUpper(l) {
+	case "DEBUG":
+		return zapcore.DebugLevel, nil
+	case "WARN", "WARNING":
+		return zapcore.WarnLevel, nil
+	case "ERROR", "ERR":
+		return zapcore.ErrorLevel, nil
+	case "DPANIC":
+		return zapcore.DPanicLevel, nil
+	case "PANIC":
+		return zapcore.PanicLevel, nil
+	case "FATAL":
+		return zapcore.FatalLevel, nil
+	case "INFO", "INF", "":
+		return zapcore.InfoLevel, nil
+	default:
+		return zapcore.InfoLevel, fmt.Errorf("unknown log level: %s", l)
+	}
+}
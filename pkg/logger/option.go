@@ -110,27 +110,10 @@ func WithLogCompress(compress bool) Option {
 	}
 }
 
-// WithLogLevel 로그 레벨 설정
+// WithLogLevel 로그 레벨 설정 (알 수 없는 레벨이면 INFO)
 func WithLogLevel(level string) Option {
 	return func(c *config) {
-		switch strings.ToUpper(level) {
-		case "DEBUG":
-			c.level = zapcore.DebugLevel
-		case "WARN", "WARNING":
-			c.level = zapcore.WarnLevel
-		case "ERROR", "ERR":
-			c.level = zapcore.ErrorLevel
-		case "DPANIC":
-			c.level = zapcore.DPanicLevel
-		case "PANIC":
-			c.level = zapcore.PanicLevel
-		case "FATAL":
-			c.level = zapcore.FatalLevel
-		case "INFO", "INF":
-			fallthrough
-		default:
-			c.level = zapcore.InfoLevel
-		}
+		c.level, _ = ParseLevel(level)
 	}
 }
 
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const (
+	Namespace string = "stradvision"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	// kubernetes informer
+	InformerEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "informer_events_total",
+		Help:      "Number of kubernetes events received from the informer by type (add, update).",
+	}, []string{"type"})
+	InformerSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "informer_skipped_total",
+		Help:      "Number of objects from the initial informer list skipped because they were already published.",
+	})
+
+	InformerDeduplicated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "informer_deduplicated_total",
+		Help:      "Number of informer updates not published by reason (unchanged, insignificant, aggregated).",
+	}, []string{"reason"})
+
+	// kafka producer
+	KafkaProduced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "kafka_produced_total",
+		Help:      "Number of kafka messages acknowledged by the broker.",
+	}, []string{"topic", "partition"})
+	KafkaProduceFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "kafka_produce_failed_total",
+		Help:      "Number of kafka messages that failed to be produced.",
+	}, []string{"topic", "partition"})
+
+	// kafka consumer
+	KafkaConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "kafka_consumed_total",
+		Help:      "Number of kafka messages consumed.",
+	}, []string{"topic", "partition"})
+
+	// leader election
+	Leader = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "leader",
+		Help:      "Whether this replica is the leader (1) or a standby (0).",
+	})
+
+	// event buffer
+	BufferFlushSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "buffer_flush_size",
+		Help:      "Number of events per event buffer flush.",
+		Buckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000},
+	})
+	BufferFlushDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "buffer_flush_duration_seconds",
+		Help:      "Time spent flushing the event buffer.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	BufferDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "buffer_dropped_total",
+		Help:      "Number of events dropped because the event buffer queue was full.",
+	})
+
+	// elasticsearch
+	ESBulkIndexed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "es_bulk_items_indexed_total",
+		Help:      "Number of documents indexed by elasticsearch bulk requests.",
+	})
+	ESBulkFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "es_bulk_items_failed_total",
+		Help:      "Number of documents rejected by elasticsearch bulk requests by status.",
+	}, []string{"status"})
+	ESBulkRetried = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "es_bulk_items_retried_total",
+		Help:      "Number of documents resent after a retryable bulk failure.",
+	})
+	ESBulkRequestFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "es_bulk_requests_failed_total",
+		Help:      "Number of elasticsearch bulk requests that failed without a response.",
+	})
+
+	// dead letter queue
+	DLQSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "dlq_sent_total",
+		Help:      "Number of events sent to the dead letter queue.",
+	}, []string{"topic"})
+
+	// storage
+	StorageWrittenBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "storage_written_bytes_total",
+		Help:      "Number of bytes written to storage files.",
+	})
+	StorageFilesRotated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "storage_files_rotated_total",
+		Help:      "Number of storage file rotations.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		InformerEvents, InformerSkipped, InformerDeduplicated,
+		KafkaProduced, KafkaProduceFailed, KafkaConsumed,
+		Leader,
+		BufferFlushSize, BufferFlushDuration, BufferDropped,
+		ESBulkIndexed, ESBulkFailed, ESBulkRetried, ESBulkRequestFailed,
+		DLQSent,
+		StorageWrittenBytes, StorageFilesRotated,
+	)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	DefaultAddress string = ":8080"
+	MetricsPath    string = "/metrics"
+)
+
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+}
+
+// NewServer /metrics endpoint를 제공하는 http 서버 생성
+// addr: 없으면 DefaultAddress
+func NewServer(addr string) *Server {
+	if addr == "" {
+		addr = DefaultAddress
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		mux: mux,
+	}
+}
+
+// Handle endpoint 추가
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run http 서버 실행
+// Close로 종료된 경우에는 nil을 반환
+func (s *Server) Run() error {
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Close http 서버 종료
+func (s *Server) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = s.server.Shutdown(ctx)
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CheckpointExtension string = ".checkpoint"
+)
+
+// checkpointPath 파일의 checkpoint 경로
+func (h *Handler) checkpointPath(file string) string {
+	return filepath.Join(h.path, file+CheckpointExtension)
+}
+
+// ReadCheckpoint 파일에서 처리가 끝난 위치(byte offset)를 반환
+// checkpoint가 없으면 0을 반환
+func (h *Handler) ReadCheckpoint(file string) (int64, error) {
+	data, err := os.ReadFile(h.checkpointPath(file))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", file, err)
+	}
+
+	return offset, nil
+}
+
+// WriteCheckpoint 파일에서 처리가 끝난 위치(byte offset)를 기록
+// 임시 파일에 기록한 뒤 rename하여 중간에 종료되어도 checkpoint가 깨지지 않도록 함
+func (h *Handler) WriteCheckpoint(file string, offset int64) error {
+	path := h.checkpointPath(file)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to rename checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveCheckpoint 파일의 checkpoint 삭제
+func (h *Handler) RemoveCheckpoint(file string) error {
+	if err := os.Remove(h.checkpointPath(file)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// OpenFile 읽기 전용으로 파일 열기
+func (h *Handler) OpenFile(file string) (*os.File, error) {
+	return os.Open(filepath.Join(h.path, file))
+}
+
+// StatFile 파일 정보 반환
+func (h *Handler) StatFile(file string) (os.FileInfo, error) {
+	return os.Stat(filepath.Join(h.path, file))
+}
+
+// ArchiveFile 파일을 archivePath로 이동하고 checkpoint 삭제
+// 파일 번호는 다시 사용될 수 있으므로 이동한 시각을 파일명 뒤에 추가
+func (h *Handler) ArchiveFile(file, archivePath string) error {
+	if err := os.MkdirAll(archivePath, 0755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	archived := fmt.Sprintf("%s.%s", file, time.Now().Format("20060102150405"))
+	if err := os.Rename(filepath.Join(h.path, file), filepath.Join(archivePath, archived)); err != nil {
+		return fmt.Errorf("failed to archive file: %w", err)
+	}
+
+	return h.RemoveCheckpoint(file)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := t.TempDir()
+
+	sHandler, err := NewHandler("event", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sHandler.WriteData([]byte("0123456789\n")); err != nil {
+		t.Fatal(err)
+	}
+	file := sHandler.GetCurrentFile()
+
+	// checkpoint가 없으면 처음부터
+	offset, err := sHandler.ReadCheckpoint(filepath.Base(file))
+	if err != nil || offset != 0 {
+		t.Fatalf("ReadCheckpoint() = %d, %v, want %d, %v", offset, err, 0, nil)
+	}
+
+	if err := sHandler.WriteCheckpoint(filepath.Base(file), 11); err != nil {
+		t.Fatal(err)
+	}
+	offset, err = sHandler.ReadCheckpoint(filepath.Base(file))
+	if err != nil || offset != 11 {
+		t.Fatalf("ReadCheckpoint() = %d, %v, want %d, %v", offset, err, 11, nil)
+	}
+
+	// checkpoint 파일은 데이터 파일 목록에서 제외
+	files, err := sHandler.GetSortFileList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != "event_0" {
+		t.Fatalf("GetSortFileList() = %v, want %v", files, []string{"event_0"})
+	}
+
+	// archive 후에는 데이터 파일과 checkpoint 모두 제거
+	archive := filepath.Join(path, "archive")
+	if err := sHandler.ArchiveFile(files[0], archive); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file + CheckpointExtension); !os.IsNotExist(err) {
+		t.Errorf("checkpoint exists after archive: %v", err)
+	}
+	entries, _ := os.ReadDir(archive)
+	if len(entries) != 1 {
+		t.Errorf("archive entries = %d, want %d", len(entries), 1)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"example.com/stradvision-project/pkg/metrics"
 )
 
 type Handler struct {
@@ -29,6 +32,7 @@ func NewHandler(name, path string, options ...Option) (*Handler, error) {
 	}
 
 	// 마지막 파일 번호 추출
+	// 기존 파일을 덮어쓰지 않도록 마지막 파일 다음 번호부터 기록 (이전 파일은 더 이상 기록하지 않음)
 	files, err := handler.GetSortFileList()
 	if err != nil {
 		return nil, err
@@ -38,7 +42,7 @@ func NewHandler(name, path string, options ...Option) (*Handler, error) {
 		handler.currentCount = 0
 	} else {
 		lastCount := extractNumber(files[len(files)-1])
-		handler.currentCount = lastCount
+		handler.currentCount = lastCount + 1
 	}
 
 	return handler, nil
@@ -62,7 +66,7 @@ func (h *Handler) GetFileList() ([]string, error) {
 			continue
 		}
 
-		if strings.Contains(entry.Name(), h.name) {
+		if h.isDataFile(entry.Name()) {
 			files = append(files, entry.Name())
 		}
 	}
@@ -70,6 +74,18 @@ func (h *Handler) GetFileList() ([]string, error) {
 	return files, nil
 }
 
+// isDataFile {name}_{number} 형식의 데이터 파일인지 확인
+// checkpoint 등 같은 경로의 다른 파일은 제외
+func (h *Handler) isDataFile(file string) bool {
+	suffix, ok := strings.CutPrefix(file, h.name+"_")
+	if !ok {
+		return false
+	}
+
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
 // GetSortFileList 파일 목록을 정렬해서 반환
 func (h *Handler) GetSortFileList() ([]string, error) {
 	files, err := h.GetFileList()
@@ -88,7 +104,7 @@ func (h *Handler) RemoveFile(file string) error {
 		return err
 	}
 
-	return nil
+	return h.RemoveCheckpoint(file)
 }
 
 // RemoveFiles 파일 목록을 받아서 삭제
@@ -140,11 +156,15 @@ func (h *Handler) WriteData(data []byte) error {
 			return fmt.Errorf("failed to check and remove: %w", err)
 		}
 
+		if err := h.currentFile.Sync(); err != nil {
+			return fmt.Errorf("failed to sync file: %w", err)
+		}
 		if err := h.currentFile.Close(); err != nil {
 			return fmt.Errorf("failed to close file: %w", err)
 		}
 
 		h.currentCount++
+		metrics.StorageFilesRotated.Inc()
 		file, err := os.Create(filepath.Join(h.path, fmt.Sprintf("%s_%d", h.name, h.currentCount)))
 		if err != nil {
 			return fmt.Errorf("failed to create file: %w", err)
@@ -153,9 +173,41 @@ func (h *Handler) WriteData(data []byte) error {
 	}
 
 	// 데이터 기록
-	if _, err := h.currentFile.Write(data); err != nil {
+	n, err := h.currentFile.Write(data)
+	metrics.StorageWrittenBytes.Add(float64(n))
+	if err != nil {
 		return fmt.Errorf("failed to write data: %w", err)
 	}
 
 	return nil
 }
+
+// Close 현재 파일을 디스크에 반영하고 닫기
+func (h *Handler) Close() error {
+	if h.currentFile == nil {
+		return nil
+	}
+
+	if err := h.currentFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := h.currentFile.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	h.currentFile = nil
+
+	return nil
+}
+
+// Sync 현재 파일에 기록된 데이터를 디스크에 반영
+func (h *Handler) Sync() error {
+	if h.currentFile == nil {
+		return nil
+	}
+
+	if err := h.currentFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	return nil
+}